@@ -2,12 +2,21 @@ package ast
 
 import (
 	token "Lisa/lexToken"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode"
 )
 
 // Node is the base element of an AST tree.
 type Node interface {
 	// TokenLiteral is the literal of the parsed node.
 	TokenLiteral() string
+	// Pos is the position of the first character of the node in the source code.
+	Pos() token.Position
+	// String is the canonical source code of the node, every operation is enclosed by parentheses, e.g. '(1 + (2 * 3))'.
+	// It's meant for debugging and golden tests.
+	String() string
 }
 
 // Statement node is an instruction ( action ) node. It performs actions and doesn't produce value.
@@ -31,6 +40,9 @@ type Expression interface {
 type ProgramRoot struct {
 	// Statements stores a series of statements (which is an interface, any node that fits Statement counts.) that is contained in our program.
 	Statements []Statement
+	// Comments are all the comments in the source code, in the order they appear.
+	// They aren't part of the tree, Walk and String ignore them.
+	Comments []token.Comment
 }
 
 func (p *ProgramRoot) TokenLiteral() string {
@@ -41,6 +53,20 @@ func (p *ProgramRoot) TokenLiteral() string {
 	// No statements stored in root node.
 	return ""
 }
+func (p *ProgramRoot) Pos() token.Position {
+	if len(p.Statements) > 0 {
+		return p.Statements[0].Pos()
+	}
+	return token.Position{}
+}
+func (p *ProgramRoot) String() string {
+	stmts := make([]string, 0, len(p.Statements))
+	for _, s := range p.Statements {
+		stmts = append(stmts, s.String())
+	}
+	// One statement per line.
+	return strings.Join(stmts, "\n")
+}
 
 // VarStatement node. Should hold the Name of the identifier, the value for the expression, and its own Token.
 type VarStatement struct {
@@ -53,6 +79,10 @@ type VarStatement struct {
 }
 
 func (v *VarStatement) TokenLiteral() string { return v.Token.Literal }
+func (v *VarStatement) Pos() token.Position  { return v.Token.Pos() }
+func (v *VarStatement) String() string {
+	return fmt.Sprintf("%s %s = %s;", v.TokenLiteral(), v.Name.String(), nodeString(v.Value))
+}
 
 // statementNode categorizes VarStatement node as a statement node.
 func (v *VarStatement) statementNode() {}
@@ -64,6 +94,10 @@ type ReturnStatement struct {
 }
 
 func (r *ReturnStatement) TokenLiteral() string { return r.Token.Literal }
+func (r *ReturnStatement) Pos() token.Position  { return r.Token.Pos() }
+func (r *ReturnStatement) String() string {
+	return fmt.Sprintf("%s %s;", r.TokenLiteral(), nodeString(r.ReturnValue))
+}
 
 // statementNode categorizes ReturnStatement node as a statement node.
 func (r *ReturnStatement) statementNode() {}
@@ -75,9 +109,58 @@ type ExpressionStatement struct {
 }
 
 func (e *ExpressionStatement) TokenLiteral() string { return e.Token.Literal }
+func (e *ExpressionStatement) Pos() token.Position  { return e.Token.Pos() }
+func (e *ExpressionStatement) String() string       { return nodeString(e.Expression) + ";" }
 
 func (e *ExpressionStatement) statementNode() {}
 
+// BlockStatement is a series of statements enclosed by braces, e.g. the consequence of an if expression.
+type BlockStatement struct {
+	Token      *token.Token // The '{' token.
+	Statements []Statement
+	// Rbrace is the position of the closing '}', it isn't valid if the block is unterminated.
+	Rbrace token.Position
+}
+
+func (b *BlockStatement) TokenLiteral() string { return b.Token.Literal }
+func (b *BlockStatement) Pos() token.Position  { return b.Token.Pos() }
+func (b *BlockStatement) String() string {
+	if len(b.Statements) == 0 {
+		return "{ }"
+	}
+	stmts := make([]string, 0, len(b.Statements))
+	for _, s := range b.Statements {
+		stmts = append(stmts, s.String())
+	}
+	return "{ " + strings.Join(stmts, " ") + " }"
+}
+
+func (b *BlockStatement) statementNode() {}
+
+// BadStatement is a placeholder for a statement that contains syntax errors.
+// The parser skips the tokens of the broken statement, from Token to End.
+type BadStatement struct {
+	Token *token.Token // The first token of the broken statement.
+	// End is the position right after the last skipped token.
+	End token.Position
+}
+
+func (b *BadStatement) TokenLiteral() string { return b.Token.Literal }
+func (b *BadStatement) Pos() token.Position  { return b.Token.Pos() }
+func (b *BadStatement) String() string       { return "<bad statement>;" }
+
+func (b *BadStatement) statementNode() {}
+
+// BadExpression is a placeholder for an expression that contains syntax errors.
+type BadExpression struct {
+	Token *token.Token // The token the broken expression starts at.
+}
+
+func (b *BadExpression) expressionNode()      {}
+func (b *BadExpression) TokenLiteral() string { return b.Token.Literal }
+func (b *BadExpression) Pos() token.Position  { return b.Token.Pos() }
+func (b *BadExpression) String() string       { return "<bad expression>" }
+
 // IdentifierExpression node. In the context of a programming language's abstract syntax tree (AST),
 // an identifier is typically associated with a declaration or a statement node.
 // This is because an identifier is often used to name and reference variables, functions, or other program entities,
@@ -91,6 +174,8 @@ func (i *IdentifierExpression) TokenLiteral() string {
 	// Return the parsed literal from the token.
 	return i.Token.Literal
 }
+func (i *IdentifierExpression) Pos() token.Position { return i.Token.Pos() }
+func (i *IdentifierExpression) String() string      { return i.Value }
 
 // expressionNode categorizes Identifier as an expression node.
 func (i *IdentifierExpression) expressionNode() {}
@@ -101,12 +186,40 @@ type IntegerLiteralExpression struct {
 	// Value is the field that's going to contain the actual value the integer literal represents.
 	// When we build an IntegerLiteralExpression, we have to convert the string stored in IntegerLiteralExpression.Token.Literal to an int64.git
 	Value int64
+	// Big is the value if it's out of the range of an int64, in which case Value is 0, otherwise it's nil.
+	Big *big.Int
 }
 
 func (i *IntegerLiteralExpression) expressionNode() {}
 func (i *IntegerLiteralExpression) TokenLiteral() string {
 	return i.Token.Literal
 }
+func (i *IntegerLiteralExpression) Pos() token.Position { return i.Token.Pos() }
+func (i *IntegerLiteralExpression) String() string      { return i.Token.Literal }
+
+// FloatLiteral node, e.g. '3.14' or '1.5e-3'.
+type FloatLiteral struct {
+	Token *token.Token
+	// Value is the value of Token.Literal, the nearest float64 to it.
+	Value float64
+}
+
+func (f *FloatLiteral) expressionNode()      {}
+func (f *FloatLiteral) TokenLiteral() string { return f.Token.Literal }
+func (f *FloatLiteral) Pos() token.Position  { return f.Token.Pos() }
+func (f *FloatLiteral) String() string       { return f.Token.Literal }
+
+// StringLiteral node, e.g. '"hello"'.
+type StringLiteral struct {
+	Token *token.Token
+	// Value is the value of the string, with escape sequences already resolved by the lexer.
+	Value string
+}
+
+func (s *StringLiteral) expressionNode()      {}
+func (s *StringLiteral) TokenLiteral() string { return s.Token.Literal }
+func (s *StringLiteral) Pos() token.Position  { return s.Token.Pos() }
+func (s *StringLiteral) String() string       { return Quote(s.Value) }
 
 type BooleanExpression struct {
 	Token *token.Token
@@ -117,21 +230,219 @@ func (b *BooleanExpression) expressionNode() {}
 func (b *BooleanExpression) TokenLiteral() string {
 	return b.Token.Literal
 }
+func (b *BooleanExpression) Pos() token.Position { return b.Token.Pos() }
+func (b *BooleanExpression) String() string      { return b.Token.Literal }
 
+// IfExpression node, e.g. 'if (x > y) { x; } else { y; }'.
+// It's an expression, thus it yields the value of whichever block is evaluated.
 type IfExpression struct {
-	Token *token.Token
+	Token *token.Token // The 'if' token.
+	// Condition is the expression inside the parentheses.
+	Condition Expression
+	// Consequence is the block evaluated when Condition is truthy.
+	Consequence *BlockStatement
+	// Alternative is the block after 'else', nil if there's no 'else'.
+	Alternative *BlockStatement
 }
 
 func (i *IfExpression) expressionNode() {}
 func (i *IfExpression) TokenLiteral() string {
 	return i.Token.Literal
 }
+func (i *IfExpression) Pos() token.Position { return i.Token.Pos() }
+func (i *IfExpression) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("if (%s) %s", nodeString(i.Condition), i.Consequence.String()))
+	if i.Alternative != nil {
+		sb.WriteString(" else " + i.Alternative.String())
+	}
+	return sb.String()
+}
 
 type PrefixExpression struct {
-	Operator string
-	Token    *token.Token // The prefix token, e.g. '!' or '-'.
-    RightToken Expression // Something at the right of the prefix operator, e.g. 'isCute' in '!isCute', '5' in '-5'.
+	Operator   string
+	Token      *token.Token // The prefix token, e.g. '!' or '-'.
+	RightToken Expression   // Something at the right of the prefix operator, e.g. 'isCute' in '!isCute', '5' in '-5'.
 }
 
 func (p *PrefixExpression) expressionNode()      {}
 func (p *PrefixExpression) TokenLiteral() string { return p.Token.Literal }
+func (p *PrefixExpression) Pos() token.Position  { return p.Token.Pos() }
+func (p *PrefixExpression) String() string {
+	return fmt.Sprintf("(%s%s)", p.Operator, nodeString(p.RightToken))
+}
+
+// InfixExpression is an expression with an operator between two operands, e.g. '5 + 6'.
+type InfixExpression struct {
+	Token    *token.Token // The operator token, e.g. '+'.
+	Left     Expression
+	Operator string
+	Right    Expression
+}
+
+func (i *InfixExpression) expressionNode()      {}
+func (i *InfixExpression) TokenLiteral() string { return i.Token.Literal }
+
+// Pos of an infix expression is the position of its left operand, not the operator.
+func (i *InfixExpression) Pos() token.Position { return i.Left.Pos() }
+func (i *InfixExpression) String() string {
+	return fmt.Sprintf("(%s %s %s)", nodeString(i.Left), i.Operator, nodeString(i.Right))
+}
+
+// FunctionLiteral node, e.g. 'fn(x, y) { return x + y; }'.
+// Functions are first-class values, so a function literal is an expression.
+type FunctionLiteral struct {
+	Token      *token.Token // The 'fn' token.
+	Parameters []*IdentifierExpression
+	Body       *BlockStatement
+}
+
+func (f *FunctionLiteral) expressionNode()      {}
+func (f *FunctionLiteral) TokenLiteral() string { return f.Token.Literal }
+func (f *FunctionLiteral) Pos() token.Position  { return f.Token.Pos() }
+func (f *FunctionLiteral) String() string {
+	params := make([]string, 0, len(f.Parameters))
+	for _, p := range f.Parameters {
+		params = append(params, p.String())
+	}
+	return fmt.Sprintf("%s(%s) %s", f.TokenLiteral(), strings.Join(params, ", "), f.Body.String())
+}
+
+// CallExpression node, e.g. 'add(1, 2)' or 'fn(x) { x; }(5)'.
+type CallExpression struct {
+	Token *token.Token // The '(' token.
+	// Function is the expression that evaluates to the called function, either an identifier or a function literal.
+	Function  Expression
+	Arguments []Expression
+}
+
+func (c *CallExpression) expressionNode()      {}
+func (c *CallExpression) TokenLiteral() string { return c.Token.Literal }
+
+// Pos of a call expression is the position of the called function, not the '('.
+func (c *CallExpression) Pos() token.Position { return c.Function.Pos() }
+func (c *CallExpression) String() string {
+	args := make([]string, 0, len(c.Arguments))
+	for _, a := range c.Arguments {
+		args = append(args, nodeString(a))
+	}
+	return fmt.Sprintf("%s(%s)", nodeString(c.Function), strings.Join(args, ", "))
+}
+
+// ArrayLiteral node, e.g. '[1, 2 * 2, "three"]'.
+type ArrayLiteral struct {
+	Token    *token.Token // The '[' token.
+	Elements []Expression
+}
+
+func (a *ArrayLiteral) expressionNode()      {}
+func (a *ArrayLiteral) TokenLiteral() string { return a.Token.Literal }
+func (a *ArrayLiteral) Pos() token.Position  { return a.Token.Pos() }
+func (a *ArrayLiteral) String() string {
+	elements := make([]string, 0, len(a.Elements))
+	for _, e := range a.Elements {
+		elements = append(elements, nodeString(e))
+	}
+	return fmt.Sprintf("[%s]", strings.Join(elements, ", "))
+}
+
+// IndexExpression node, e.g. 'arr[1]'.
+type IndexExpression struct {
+	Token *token.Token // The '[' token.
+	// Left is the expression that evaluates to the indexed value.
+	Left  Expression
+	Index Expression
+}
+
+func (i *IndexExpression) expressionNode()      {}
+func (i *IndexExpression) TokenLiteral() string { return i.Token.Literal }
+
+// Pos of an index expression is the position of the indexed expression, not the '['.
+func (i *IndexExpression) Pos() token.Position { return i.Left.Pos() }
+func (i *IndexExpression) String() string {
+	return fmt.Sprintf("(%s[%s])", nodeString(i.Left), nodeString(i.Index))
+}
+
+// SliceExpression node, e.g. 'arr[1:3]', 'arr[1:]' or 'arr[:3]'.
+type SliceExpression struct {
+	Token *token.Token // The '[' token.
+	// Left is the expression that evaluates to the sliced value.
+	Left Expression
+	// Low and High are the bounds of the slice, either of them is nil when it's omitted.
+	Low  Expression
+	High Expression
+}
+
+func (s *SliceExpression) expressionNode()      {}
+func (s *SliceExpression) TokenLiteral() string { return s.Token.Literal }
+
+// Pos of a slice expression is the position of the sliced expression, not the '['.
+func (s *SliceExpression) Pos() token.Position { return s.Left.Pos() }
+func (s *SliceExpression) String() string {
+	var low, high string
+	if s.Low != nil {
+		low = s.Low.String()
+	}
+	if s.High != nil {
+		high = s.High.String()
+	}
+	return fmt.Sprintf("(%s[%s:%s])", nodeString(s.Left), low, high)
+}
+
+// HashLiteral node, e.g. '{"a": 1, 2: true}'.
+type HashLiteral struct {
+	Token *token.Token // The '{' token.
+	// Pairs are in the order they're written.
+	Pairs []HashPair
+}
+
+// HashPair is a key and its value in a hash literal.
+type HashPair struct {
+	Key   Expression
+	Value Expression
+}
+
+func (h *HashLiteral) expressionNode()      {}
+func (h *HashLiteral) TokenLiteral() string { return h.Token.Literal }
+func (h *HashLiteral) Pos() token.Position  { return h.Token.Pos() }
+func (h *HashLiteral) String() string {
+	pairs := make([]string, 0, len(h.Pairs))
+	for _, pair := range h.Pairs {
+		pairs = append(pairs, nodeString(pair.Key)+": "+nodeString(pair.Value))
+	}
+	return fmt.Sprintf("{%s}", strings.Join(pairs, ", "))
+}
+
+// nodeString returns the String of node, or '<nil>' for a missing node.
+func nodeString(node Node) string {
+	if node == nil {
+		return "<nil>"
+	}
+	return node.String()
+}
+
+// Quote returns s as a double-quoted string literal, escaped the way the lexer reads it back.
+func Quote(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, ch := range s {
+		switch ch {
+		case '\\':
+			sb.WriteString(`\\`)
+		case '"':
+			sb.WriteString(`\"`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			if unicode.IsPrint(ch) {
+				sb.WriteRune(ch)
+			} else {
+				sb.WriteString(fmt.Sprintf(`\u{%X}`, ch))
+			}
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
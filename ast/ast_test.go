@@ -0,0 +1,54 @@
+package ast
+
+import (
+	token "Lisa/lexToken"
+	"testing"
+)
+
+func TestProgramRoot_String(t *testing.T) {
+	// var myVar = -anotherVar;
+	// return "hi";
+	astRoot := &ProgramRoot{
+		Statements: []Statement{
+			&VarStatement{
+				Token: token.New(token.VAR, "var"),
+				Name:  &IdentifierExpression{Token: token.New(token.IDENT, "myVar"), Value: "myVar"},
+				Value: &PrefixExpression{
+					Operator:   "-",
+					Token:      token.New(token.MINUS, "-"),
+					RightToken: &IdentifierExpression{Token: token.New(token.IDENT, "anotherVar"), Value: "anotherVar"},
+				},
+			},
+			&ReturnStatement{
+				Token:       token.New(token.RETURN, "return"),
+				ReturnValue: &StringLiteral{Token: token.New(token.STRING, "hi"), Value: "hi"},
+			},
+			&BadStatement{Token: token.New(token.VAR, "var")},
+		},
+	}
+
+	expected := "var myVar = (-anotherVar);\nreturn \"hi\";\n<bad statement>;"
+	if astRoot.String() != expected {
+		t.Errorf("error String(): expected %q, got %q.\n", expected, astRoot.String())
+	}
+}
+
+func TestQuote(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected string
+	}{
+		{"hello", `"hello"`},
+		{"café 日本", `"café 日本"`},
+		{"a\nb\tc", `"a\nb\tc"`},
+		{`say "hi" \o/`, `"say \"hi\" \\o/"`},
+		{"\x00\u200b", `"\u{0}\u{200B}"`},
+	}
+
+	for i, tc := range testCases {
+		re := Quote(tc.value)
+		if re != tc.expected {
+			t.Errorf("tests[%d] - error quoting: expected %s, got %s.\n", i, tc.expected, re)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package ast
+
+import "fmt"
+
+// Visitor's Visit method is called for every node encountered by Walk.
+// If the returned visitor w is not nil, Walk visits each of the children of node with w, followed by a call of w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses the tree rooted at node in depth-first order.
+// It starts by calling v.Visit(node), node must not be nil.
+// Missing children (e.g. the Alternative of an IfExpression without 'else') are skipped.
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	// Statements.
+	case *ProgramRoot:
+		walkStatements(v, n.Statements)
+	case *VarStatement:
+		Walk(v, n.Name)
+		if n.Value != nil {
+			Walk(v, n.Value)
+		}
+	case *ReturnStatement:
+		if n.ReturnValue != nil {
+			Walk(v, n.ReturnValue)
+		}
+	case *ExpressionStatement:
+		if n.Expression != nil {
+			Walk(v, n.Expression)
+		}
+	case *BlockStatement:
+		walkStatements(v, n.Statements)
+	case *BadStatement:
+		// Nothing to walk.
+
+	// Expressions.
+	case *IdentifierExpression, *IntegerLiteralExpression, *FloatLiteral, *StringLiteral, *BooleanExpression, *BadExpression:
+		// Nothing to walk.
+	case *PrefixExpression:
+		Walk(v, n.RightToken)
+	case *InfixExpression:
+		Walk(v, n.Left)
+		Walk(v, n.Right)
+	case *IfExpression:
+		Walk(v, n.Condition)
+		Walk(v, n.Consequence)
+		if n.Alternative != nil {
+			Walk(v, n.Alternative)
+		}
+	case *FunctionLiteral:
+		for _, param := range n.Parameters {
+			Walk(v, param)
+		}
+		Walk(v, n.Body)
+	case *CallExpression:
+		Walk(v, n.Function)
+		for _, arg := range n.Arguments {
+			Walk(v, arg)
+		}
+	case *ArrayLiteral:
+		for _, e := range n.Elements {
+			Walk(v, e)
+		}
+	case *HashLiteral:
+		for _, pair := range n.Pairs {
+			Walk(v, pair.Key)
+			Walk(v, pair.Value)
+		}
+	case *IndexExpression:
+		Walk(v, n.Left)
+		Walk(v, n.Index)
+	case *SliceExpression:
+		Walk(v, n.Left)
+		if n.Low != nil {
+			Walk(v, n.Low)
+		}
+		if n.High != nil {
+			Walk(v, n.High)
+		}
+
+	default:
+		panic(fmt.Sprintf("ast.Walk: unexpected node type %T", n))
+	}
+
+	v.Visit(nil)
+}
+
+func walkStatements(v Visitor, stmts []Statement) {
+	for _, stmt := range stmts {
+		Walk(v, stmt)
+	}
+}
+
+// inspector adapts a function to a Visitor.
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses the tree rooted at node in depth-first order.
+// It starts by calling f(node), node must not be nil.
+// If f returns true, Inspect invokes f recursively for each of the children of node, followed by a call of f(nil).
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
+
+// Rewrite traverses the tree rooted at node in depth-first order and replaces every node with the result of f.
+// The children of a node are rewritten before the node itself is passed to f, and the fields of the node are updated in place.
+// f should return the node it's given to keep it.
+// It returns the node that replaces the root.
+//
+// A field of an interface type (e.g. VarStatement.Value) can be replaced by any node of that interface,
+// while a field of a concrete type (e.g. VarStatement.Name, IfExpression.Consequence) can only be replaced by a node of the same type.
+// Rewrite panics otherwise.
+func Rewrite(node Node, f func(Node) Node) Node {
+	switch n := node.(type) {
+	// Statements.
+	case *ProgramRoot:
+		rewriteStatements(n.Statements, f)
+	case *VarStatement:
+		n.Name = rewriteAs(n.Name, f)
+		if n.Value != nil {
+			n.Value = rewriteAs(n.Value, f)
+		}
+	case *ReturnStatement:
+		if n.ReturnValue != nil {
+			n.ReturnValue = rewriteAs(n.ReturnValue, f)
+		}
+	case *ExpressionStatement:
+		if n.Expression != nil {
+			n.Expression = rewriteAs(n.Expression, f)
+		}
+	case *BlockStatement:
+		rewriteStatements(n.Statements, f)
+	case *BadStatement:
+		// Nothing to rewrite.
+
+	// Expressions.
+	case *IdentifierExpression, *IntegerLiteralExpression, *FloatLiteral, *StringLiteral, *BooleanExpression, *BadExpression:
+		// Nothing to rewrite.
+	case *PrefixExpression:
+		n.RightToken = rewriteAs(n.RightToken, f)
+	case *InfixExpression:
+		n.Left = rewriteAs(n.Left, f)
+		n.Right = rewriteAs(n.Right, f)
+	case *IfExpression:
+		n.Condition = rewriteAs(n.Condition, f)
+		n.Consequence = rewriteAs(n.Consequence, f)
+		if n.Alternative != nil {
+			n.Alternative = rewriteAs(n.Alternative, f)
+		}
+	case *FunctionLiteral:
+		for i, param := range n.Parameters {
+			n.Parameters[i] = rewriteAs(param, f)
+		}
+		n.Body = rewriteAs(n.Body, f)
+	case *CallExpression:
+		n.Function = rewriteAs(n.Function, f)
+		for i, arg := range n.Arguments {
+			n.Arguments[i] = rewriteAs(arg, f)
+		}
+	case *ArrayLiteral:
+		for i, e := range n.Elements {
+			n.Elements[i] = rewriteAs(e, f)
+		}
+	case *HashLiteral:
+		for i, pair := range n.Pairs {
+			n.Pairs[i].Key = rewriteAs(pair.Key, f)
+			n.Pairs[i].Value = rewriteAs(pair.Value, f)
+		}
+	case *IndexExpression:
+		n.Left = rewriteAs(n.Left, f)
+		n.Index = rewriteAs(n.Index, f)
+	case *SliceExpression:
+		n.Left = rewriteAs(n.Left, f)
+		if n.Low != nil {
+			n.Low = rewriteAs(n.Low, f)
+		}
+		if n.High != nil {
+			n.High = rewriteAs(n.High, f)
+		}
+
+	default:
+		panic(fmt.Sprintf("ast.Rewrite: unexpected node type %T", n))
+	}
+
+	return f(node)
+}
+
+func rewriteStatements(stmts []Statement, f func(Node) Node) {
+	for i, stmt := range stmts {
+		stmts[i] = rewriteAs(stmt, f)
+	}
+}
+
+// rewriteAs rewrites node and makes sure the result fits the field node is stored in.
+func rewriteAs[T Node](node T, f func(Node) Node) T {
+	rewritten := Rewrite(node, f)
+	t, ok := rewritten.(T)
+	if !ok {
+		panic(fmt.Sprintf("ast.Rewrite: cannot replace %T with %T", node, rewritten))
+	}
+	return t
+}
@@ -0,0 +1,161 @@
+package ast_test
+
+import (
+	"Lisa/ast"
+	token "Lisa/lexToken"
+	"Lisa/lexer"
+	"Lisa/parser"
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+func parse(t *testing.T, input string) *ast.ProgramRoot {
+	t.Helper()
+	p := parser.New(lexer.New(input))
+	astRoot := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("error parsing %q: %v", input, p.Errors())
+	}
+	return astRoot
+}
+
+// countingVisitor counts the visited nodes by type.
+type countingVisitor map[string]int
+
+func (cv countingVisitor) Visit(node ast.Node) ast.Visitor {
+	if node != nil {
+		cv[fmt.Sprintf("%T", node)]++
+	}
+	return cv
+}
+
+func TestWalk(t *testing.T) {
+	astRoot := parse(t, `var add = fn(a, b) { return a + b; };
+		var x = if (!true) { add(1, -2); } else { "s"; };
+		[x[0], x[1:]];
+		{"k": x};`)
+
+	cv := countingVisitor{}
+	ast.Walk(cv, astRoot)
+
+	expected := map[string]int{
+		"*ast.ProgramRoot":              1,
+		"*ast.VarStatement":             2,
+		"*ast.FunctionLiteral":          1,
+		"*ast.BlockStatement":           3,
+		"*ast.ReturnStatement":          1,
+		"*ast.InfixExpression":          1,
+		"*ast.IfExpression":             1,
+		"*ast.PrefixExpression":         2,
+		"*ast.BooleanExpression":        1,
+		"*ast.ExpressionStatement":      4,
+		"*ast.CallExpression":           1,
+		"*ast.IntegerLiteralExpression": 4,
+		"*ast.StringLiteral":            2,
+		"*ast.ArrayLiteral":             1,
+		"*ast.HashLiteral":              1,
+		"*ast.IndexExpression":          1,
+		"*ast.SliceExpression":          1,
+		// add, a, b (parameters), a, b (return), x, add (call), x (index), x (slice), x (hash).
+		"*ast.IdentifierExpression": 10,
+	}
+	for typ, count := range expected {
+		if cv[typ] != count {
+			t.Errorf("error visiting %s: expected %d, got %d.\n", typ, count, cv[typ])
+		}
+	}
+	if len(cv) != len(expected) {
+		t.Errorf("error visited types: expected %d, got %d (%v).\n", len(expected), len(cv), cv)
+	}
+}
+
+func TestInspect(t *testing.T) {
+	astRoot := parse(t, `var f = fn(x) { var y = x; y; }; f(z);`)
+
+	// Collect the identifiers, without going into function bodies.
+	var idents []string
+	ast.Inspect(astRoot, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.IdentifierExpression:
+			idents = append(idents, n.Value)
+		case *ast.BlockStatement:
+			return false
+		}
+		return true
+	})
+
+	expected := "[f x f z]"
+	if fmt.Sprint(idents) != expected {
+		t.Errorf("error inspecting identifiers: expected %s, got %v.\n", expected, idents)
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	t.Run("Constant folding", func(t *testing.T) {
+		astRoot := parse(t, `var x = 1 + 2 * 3; return -(4 - y) + 5 * 2;`)
+
+		ast.Rewrite(astRoot, func(node ast.Node) ast.Node {
+			infix, ok := node.(*ast.InfixExpression)
+			if !ok {
+				return node
+			}
+			left, lok := infix.Left.(*ast.IntegerLiteralExpression)
+			right, rok := infix.Right.(*ast.IntegerLiteralExpression)
+			if !lok || !rok {
+				return node
+			}
+
+			var value int64
+			switch infix.Operator {
+			case "+":
+				value = left.Value + right.Value
+			case "-":
+				value = left.Value - right.Value
+			case "*":
+				value = left.Value * right.Value
+			default:
+				return node
+			}
+			literal := strconv.FormatInt(value, 10)
+			return &ast.IntegerLiteralExpression{Token: &token.Token{Type: token.INT, Literal: literal, Span: left.Token.Span}, Value: value}
+		})
+
+		expected := "var x = 7;\nreturn ((-(4 - y)) + 10);"
+		if astRoot.String() != expected {
+			t.Errorf("error rewriting: expected %q, got %q.\n", expected, astRoot.String())
+		}
+	})
+
+	t.Run("Renaming identifiers", func(t *testing.T) {
+		astRoot := parse(t, `var a = fn(a) { a; }; a(1); [a[0], a[a:]]; {a: a};`)
+
+		root := ast.Rewrite(astRoot, func(node ast.Node) ast.Node {
+			if ident, ok := node.(*ast.IdentifierExpression); ok && ident.Value == "a" {
+				return &ast.IdentifierExpression{Token: ident.Token, Value: "b"}
+			}
+			return node
+		})
+
+		expected := "var b = fn(b) { b; };\nb(1);\n[(b[0]), (b[b:])];\n{b: b};"
+		if root.String() != expected {
+			t.Errorf("error rewriting: expected %q, got %q.\n", expected, root.String())
+		}
+	})
+
+	t.Run("Replacing a concrete field with another type panics", func(t *testing.T) {
+		astRoot := parse(t, `var a = 1;`)
+
+		defer func() {
+			if recover() == nil {
+				t.Errorf("error rewriting VarStatement.Name with an integer: expected panic, got none.\n")
+			}
+		}()
+		ast.Rewrite(astRoot, func(node ast.Node) ast.Node {
+			if ident, ok := node.(*ast.IdentifierExpression); ok {
+				return &ast.IntegerLiteralExpression{Token: ident.Token, Value: 1}
+			}
+			return node
+		})
+	})
+}
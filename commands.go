@@ -0,0 +1,215 @@
+package main
+
+import (
+	"Lisa/ast"
+	"Lisa/evaluator"
+	token "Lisa/lexToken"
+	"Lisa/lexer"
+	"Lisa/object"
+	"Lisa/parser"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// source is a program given to a command.
+type source struct {
+	// name is the filename, '<stdin>' or '-e', it's attached to the positions of the errors.
+	name string
+	src  []byte
+}
+
+// newFlagSet creates the flags of a command, the errors and the usage are written to stderr.
+func newFlagSet(name, args string, stderr io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: lisa %s %s\n", name, args)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// isFlagSet reports whether the flag name is given in the arguments, even if it's set to its default value (e.g. '-e ""').
+func isFlagSet(flags *flag.FlagSet, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// readSources reads the programs the arguments of a command refer to: the program of -e (expr, if it's given, even empty),
+// otherwise the files, where '-' is stdin.
+// It fails with a usage error if there's no program, or if there's more than one and single is set.
+func readSources(flags *flag.FlagSet, expr *string, single bool, stdin io.Reader, stderr io.Writer) ([]source, int) {
+	if isFlagSet(flags, "e") {
+		if flags.NArg() != 0 {
+			fmt.Fprintf(stderr, "lisa %s: -e can't be used with files\n", flags.Name())
+			return nil, exitUsage
+		}
+		return []source{{name: "-e", src: []byte(*expr)}}, exitOK
+	}
+	if flags.NArg() == 0 || (single && flags.NArg() > 1) {
+		flags.Usage()
+		return nil, exitUsage
+	}
+
+	sources := make([]source, 0, flags.NArg())
+	for _, filename := range flags.Args() {
+		var src []byte
+		var err error
+		if filename == "-" {
+			filename = "<stdin>"
+			src, err = io.ReadAll(stdin)
+		} else {
+			src, err = os.ReadFile(filename)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "lisa %s: %v\n", flags.Name(), err)
+			return nil, exitFailure
+		}
+		sources = append(sources, source{name: filename, src: src})
+	}
+	return sources, exitOK
+}
+
+// parse parses the program, the syntax errors are written to stderr, one per line.
+func parse(s source, stderr io.Writer) (*ast.ProgramRoot, int) {
+	p := parser.New(lexer.NewFile(s.name, string(s.src)))
+	root := p.ParseProgram()
+	if len(p.ParseErrors()) != 0 {
+		for _, e := range p.Errors() {
+			fmt.Fprintln(stderr, e)
+		}
+		return nil, exitSyntaxError
+	}
+	return root, exitOK
+}
+
+// runRun implements 'lisa run [-e program | file]', which evaluates a program and prints its value, unless it's null.
+// A runtime error is written to stderr.
+func runRun(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := newFlagSet("run", "[-e program | file]", stderr)
+	expr := flags.String("e", "", "run `program` instead of a file")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	sources, code := readSources(flags, expr, true, stdin, stderr)
+	if code != exitOK {
+		return code
+	}
+
+	root, code := parse(sources[0], stderr)
+	if code != exitOK {
+		return code
+	}
+
+	result := evaluator.Eval(root, evaluator.NewEnvironment())
+	if errObj, ok := result.(*object.Error); ok {
+		fmt.Fprintf(stderr, "%s: runtime error: %s\n", sources[0].name, errObj.Message)
+		return exitFailure
+	}
+	if result != nil && result != evaluator.NULL {
+		fmt.Fprintln(stdout, result.Inspect())
+	}
+	return exitOK
+}
+
+// runTokens implements 'lisa tokens [-comments] [-e program | file]', which prints the tokens of a program, one per line.
+// A program with illegal tokens results in exitSyntaxError, after all the tokens are printed.
+func runTokens(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := newFlagSet("tokens", "[-comments] [-e program | file]", stderr)
+	expr := flags.String("e", "", "print the tokens of `program` instead of a file")
+	comments := flags.Bool("comments", false, "print the comments as well")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	sources, code := readSources(flags, expr, true, stdin, stderr)
+	if code != exitOK {
+		return code
+	}
+
+	var opts []lexer.Option
+	if *comments {
+		opts = append(opts, lexer.EmitComments())
+	}
+	l := lexer.NewFile(sources[0].name, string(sources[0].src), opts...)
+
+	code = exitOK
+	for tok := l.ReadNextToken(); tok.Type != token.EOF; tok = l.ReadNextToken() {
+		fmt.Fprintf(stdout, "%d:%d\t%s\t%q\n", tok.Pos().Line, tok.Pos().Column, tok.Type, tok.Literal)
+		if tok.Type == token.ILLEGAL {
+			code = exitSyntaxError
+		}
+	}
+	return code
+}
+
+// runAST implements 'lisa ast [-e program | file]', which prints the syntax tree of a program in its canonical form, one statement per line.
+func runAST(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := newFlagSet("ast", "[-e program | file]", stderr)
+	expr := flags.String("e", "", "print the syntax tree of `program` instead of a file")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	sources, code := readSources(flags, expr, true, stdin, stderr)
+	if code != exitOK {
+		return code
+	}
+
+	root, code := parse(sources[0], stderr)
+	if code != exitOK {
+		return code
+	}
+	if len(root.Statements) != 0 {
+		fmt.Fprintln(stdout, root.String())
+	}
+	return exitOK
+}
+
+// runCheck implements 'lisa check [-e program | files]', which reports the syntax errors of programs without running them.
+func runCheck(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := newFlagSet("check", "[-e program | files]", stderr)
+	expr := flags.String("e", "", "check `program` instead of files")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	sources, code := readSources(flags, expr, false, stdin, stderr)
+	if code != exitOK {
+		return code
+	}
+
+	for _, s := range sources {
+		if _, c := parse(s, stderr); c != exitOK {
+			code = c
+		}
+	}
+	return code
+}
+
+// printError writes an error returned while processing a program to stderr.
+// Syntax errors are written one per line, like 'lisa check' does.
+func printError(stderr io.Writer, err error) {
+	var syntaxErrors parser.ErrorList
+	if !errors.As(err, &syntaxErrors) {
+		fmt.Fprintln(stderr, err)
+		return
+	}
+	for _, e := range syntaxErrors {
+		fmt.Fprintln(stderr, e)
+	}
+}
+
+// exitCodeOf returns the exit code for an error returned while processing a program.
+func exitCodeOf(err error) int {
+	var syntaxErrors parser.ErrorList
+	if errors.As(err, &syntaxErrors) {
+		return exitSyntaxError
+	}
+	return exitFailure
+}
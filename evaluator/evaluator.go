@@ -0,0 +1,649 @@
+package evaluator
+
+import (
+	"Lisa/ast"
+	"Lisa/object"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+var (
+	// NULL, TRUE and FALSE are shared since there's no need to allocate a new object every time we encounter them.
+	NULL  = &object.Null{}
+	TRUE  = &object.Boolean{Value: true}
+	FALSE = &object.Boolean{Value: false}
+)
+
+// Environment keeps track of the values bound to identifiers while evaluating a program.
+type Environment = object.Environment
+
+// NewEnvironment creates an empty *Environment to pass to Eval.
+func NewEnvironment() *Environment {
+	return object.NewEnvironment()
+}
+
+// Eval walks the given node and evaluates it to an object.Object.
+// Identifiers are resolved and bound in env.
+func Eval(node ast.Node, env *Environment) object.Object {
+	switch node := node.(type) {
+	// Statements.
+	case *ast.ProgramRoot:
+		return evalProgram(node, env)
+	case *ast.ExpressionStatement:
+		return Eval(node.Expression, env)
+	case *ast.BlockStatement:
+		return evalBlockStatement(node, env)
+	case *ast.VarStatement:
+		if node.Value == nil {
+			return newError("missing value for identifier: %s", node.Name.Value)
+		}
+		val := Eval(node.Value, env)
+		if isError(val) {
+			return val
+		}
+		env.Set(node.Name.Value, val)
+		// A var statement doesn't produce a value.
+		return NULL
+	case *ast.ReturnStatement:
+		if node.ReturnValue == nil {
+			return &object.ReturnValue{Value: NULL}
+		}
+		val := Eval(node.ReturnValue, env)
+		if isError(val) {
+			return val
+		}
+		return &object.ReturnValue{Value: val}
+
+	// Expressions.
+	case *ast.IntegerLiteralExpression:
+		return &object.Integer{Value: node.Value, Big: node.Big}
+	case *ast.FloatLiteral:
+		return &object.Float{Value: node.Value}
+	case *ast.StringLiteral:
+		return &object.String{Value: node.Value}
+	case *ast.BooleanExpression:
+		return nativeBoolToBooleanObject(node.Value)
+	case *ast.IdentifierExpression:
+		return evalIdentifier(node, env)
+	case *ast.PrefixExpression:
+		right := Eval(node.RightToken, env)
+		if isError(right) {
+			return right
+		}
+		return evalPrefixExpression(node.Operator, right)
+	case *ast.FunctionLiteral:
+		return &object.Function{Parameters: node.Parameters, Body: node.Body, Env: env}
+	case *ast.CallExpression:
+		function := Eval(node.Function, env)
+		if isError(function) {
+			return function
+		}
+		args := evalExpressions(node.Arguments, env)
+		if len(args) == 1 && isError(args[0]) {
+			return args[0]
+		}
+		return applyFunction(function, args, env)
+	case *ast.IfExpression:
+		return evalIfExpression(node, env)
+	case *ast.ArrayLiteral:
+		elements := evalExpressions(node.Elements, env)
+		if len(elements) == 1 && isError(elements[0]) {
+			return elements[0]
+		}
+		return &object.Array{Elements: elements}
+	case *ast.HashLiteral:
+		return evalHashLiteral(node, env)
+	case *ast.IndexExpression:
+		left := Eval(node.Left, env)
+		if isError(left) {
+			return left
+		}
+		index := Eval(node.Index, env)
+		if isError(index) {
+			return index
+		}
+		return evalIndexExpression(left, index)
+	case *ast.SliceExpression:
+		return evalSliceExpression(node, env)
+	case *ast.InfixExpression:
+		left := Eval(node.Left, env)
+		if isError(left) {
+			return left
+		}
+		right := Eval(node.Right, env)
+		if isError(right) {
+			return right
+		}
+		return evalInfixExpression(node.Operator, left, right)
+	case *ast.BadStatement:
+		return newError("cannot evaluate a statement with syntax errors at %s", node.Pos())
+	case *ast.BadExpression:
+		return newError("cannot evaluate an expression with syntax errors at %s", node.Pos())
+	}
+
+	return newError("unknown node: %T", node)
+}
+
+// evalProgram evaluates the statements of the program one by one and returns the value of the last one.
+// It stops early when encountering a return value (which is unwrapped) or an error.
+func evalProgram(program *ast.ProgramRoot, env *Environment) object.Object {
+	var result object.Object = NULL
+
+	for _, stmt := range program.Statements {
+		result = Eval(stmt, env)
+
+		switch result := result.(type) {
+		case *object.ReturnValue:
+			return result.Value
+		case *object.Error:
+			return result
+		}
+	}
+
+	return result
+}
+
+// evalBlockStatement evaluates the statements of the block one by one and returns the value of the last one.
+// Unlike evalProgram, a return value isn't unwrapped, so it bubbles up and stops the evaluation of the enclosing blocks as well.
+func evalBlockStatement(block *ast.BlockStatement, env *Environment) object.Object {
+	var result object.Object = NULL
+
+	for _, stmt := range block.Statements {
+		result = Eval(stmt, env)
+
+		if result != nil && (result.Type() == object.RETURN_VALUE || result.Type() == object.ERROR) {
+			return result
+		}
+	}
+
+	return result
+}
+
+// evalIfExpression evaluates the consequence if the condition is truthy, otherwise the alternative.
+// It evaluates to null when the condition isn't truthy and there's no alternative.
+func evalIfExpression(ie *ast.IfExpression, env *Environment) object.Object {
+	condition := Eval(ie.Condition, env)
+	if isError(condition) {
+		return condition
+	}
+
+	if isTruthy(condition) {
+		return Eval(ie.Consequence, env)
+	} else if ie.Alternative != nil {
+		return Eval(ie.Alternative, env)
+	}
+	return NULL
+}
+
+// evalExpressions evaluates the expressions from left to right.
+// It stops at the first error, which is returned as the only element.
+func evalExpressions(exps []ast.Expression, env *Environment) []object.Object {
+	result := make([]object.Object, 0, len(exps))
+
+	for _, e := range exps {
+		evaluated := Eval(e, env)
+		if isError(evaluated) {
+			return []object.Object{evaluated}
+		}
+		result = append(result, evaluated)
+	}
+
+	return result
+}
+
+// maxCallDepth is the maximum number of nested function calls.
+// The evaluator recurses on the Go stack, it'd overflow (which can't be recovered) long before an infinite recursion ends.
+const maxCallDepth = 10000
+
+// applyFunction calls fn with args, caller is the environment of the call expression.
+// The body is evaluated in a new environment enclosed by the one the function is defined in, with the parameters bound to args.
+// The call fails if there are already maxCallDepth calls in progress, e.g. in an infinite recursion.
+func applyFunction(fn object.Object, args []object.Object, caller *Environment) object.Object {
+	function, ok := fn.(*object.Function)
+	if !ok {
+		return newError("not a function: %s", fn.Type())
+	}
+	if len(args) != len(function.Parameters) {
+		return newError("wrong number of arguments: expected %d, got %d", len(function.Parameters), len(args))
+	}
+	if caller.Depth() >= maxCallDepth {
+		return newError("maximum recursion depth exceeded")
+	}
+
+	env := object.NewCallEnvironment(function.Env, caller)
+	for i, param := range function.Parameters {
+		env.Set(param.Value, args[i])
+	}
+
+	evaluated := Eval(function.Body, env)
+	// Unwrap the return value, otherwise it'd stop the evaluation of the caller as well.
+	if returnValue, ok := evaluated.(*object.ReturnValue); ok {
+		return returnValue.Value
+	}
+	return evaluated
+}
+
+// evalIndexExpression returns the element of an array or the value of a hash at index, e.g. 'arr[1]' or 'h["a"]'.
+func evalIndexExpression(left, index object.Object) object.Object {
+	switch left := left.(type) {
+	case *object.Array:
+		return evalArrayIndexExpression(left, index)
+	case *object.Hash:
+		return evalHashIndexExpression(left, index)
+	default:
+		return newError("index operator not supported: %s", left.Type())
+	}
+}
+
+// evalArrayIndexExpression returns the element of array at index.
+// A negative index counts from the end, e.g. 'arr[-1]' is the last element.
+func evalArrayIndexExpression(array *object.Array, index object.Object) object.Object {
+	integer, ok := index.(*object.Integer)
+	if !ok {
+		return newError("index must be an integer: got %s", index.Type())
+	}
+
+	length := int64(len(array.Elements))
+	i := integer.Value
+	if i < 0 {
+		i += length
+	}
+	if integer.Big != nil || i < 0 || i >= length {
+		return newError("index out of range: %s with length %d", integer.Inspect(), length)
+	}
+	return array.Elements[i]
+}
+
+// evalHashIndexExpression returns the value of hash at key, or null if key isn't set.
+func evalHashIndexExpression(hash *object.Hash, key object.Object) object.Object {
+	hashable, ok := key.(object.Hashable)
+	if !ok {
+		return newError("unusable as hash key: %s", key.Type())
+	}
+	value, ok := hash.Get(hashable)
+	if !ok {
+		return NULL
+	}
+	return value
+}
+
+// evalHashLiteral evaluates the keys and the values of a hash literal in the order they're written.
+// The keys must be hashable (see object.Hashable), and a key that's repeated takes the last value.
+func evalHashLiteral(node *ast.HashLiteral, env *Environment) object.Object {
+	hash := object.NewHash()
+
+	for _, pair := range node.Pairs {
+		key := Eval(pair.Key, env)
+		if isError(key) {
+			return key
+		}
+		hashable, ok := key.(object.Hashable)
+		if !ok {
+			return newError("unusable as hash key: %s", key.Type())
+		}
+
+		value := Eval(pair.Value, env)
+		if isError(value) {
+			return value
+		}
+		hash.Set(hashable, value)
+	}
+
+	return hash
+}
+
+// evalSliceExpression returns a new array with the elements of the sliced array from the low bound up to (excluding) the high bound, e.g. 'arr[1:3]'.
+// An omitted low bound is 0 and an omitted high bound is the length of the array.
+// A negative bound counts from the end, e.g. 'arr[-2:]' is the last two elements.
+func evalSliceExpression(node *ast.SliceExpression, env *Environment) object.Object {
+	left := Eval(node.Left, env)
+	if isError(left) {
+		return left
+	}
+	array, ok := left.(*object.Array)
+	if !ok {
+		return newError("slice operator not supported: %s", left.Type())
+	}
+
+	length := int64(len(array.Elements))
+	low, high := int64(0), length
+	if node.Low != nil {
+		bound, err := evalSliceBound(node.Low, env)
+		if err != nil {
+			return err
+		}
+		low = bound
+	}
+	if node.High != nil {
+		bound, err := evalSliceBound(node.High, env)
+		if err != nil {
+			return err
+		}
+		high = bound
+	}
+
+	// The bounds in the error are the ones in the expression, before the negative ones are resolved.
+	l, h := low, high
+	if l < 0 {
+		l += length
+	}
+	if h < 0 {
+		h += length
+	}
+	if l < 0 || h > length || l > h {
+		return newError("slice bounds out of range: [%d:%d] with length %d", low, high, length)
+	}
+	low, high = l, h
+
+	// Copy the elements, so the slice doesn't share them with the sliced array.
+	elements := make([]object.Object, high-low)
+	copy(elements, array.Elements[low:high])
+	return &object.Array{Elements: elements}
+}
+
+// evalSliceBound evaluates a bound of a slice expression, which must be an integer.
+func evalSliceBound(exp ast.Expression, env *Environment) (int64, *object.Error) {
+	evaluated := Eval(exp, env)
+	if err, ok := evaluated.(*object.Error); ok {
+		return 0, err
+	}
+	integer, ok := evaluated.(*object.Integer)
+	if !ok {
+		return 0, newError("slice bound must be an integer: got %s", evaluated.Type())
+	}
+	if integer.Big != nil {
+		return 0, newError("slice bound out of range: %s", integer.Inspect())
+	}
+	return integer.Value, nil
+}
+
+// evalIdentifier looks up the value bound to the identifier in env.
+func evalIdentifier(node *ast.IdentifierExpression, env *Environment) object.Object {
+	val, ok := env.Get(node.Value)
+	if !ok {
+		return newError("identifier not found: %s", node.Value)
+	}
+	return val
+}
+
+// evalPrefixExpression applies the prefix operator to the evaluated right side of the expression.
+func evalPrefixExpression(operator string, right object.Object) object.Object {
+	switch operator {
+	case "!":
+		return evalExclamationOperatorExpression(right)
+	case "-":
+		return evalMinusPrefixOperatorExpression(right)
+	default:
+		return newError("unknown operator: %s%s", operator, right.Type())
+	}
+}
+
+// evalExclamationOperatorExpression negates the truthiness of right, e.g. '!true' is false and '!5' is false.
+func evalExclamationOperatorExpression(right object.Object) object.Object {
+	return nativeBoolToBooleanObject(!isTruthy(right))
+}
+
+// evalMinusPrefixOperatorExpression negates a number, e.g. '-5' or '-2.5'.
+func evalMinusPrefixOperatorExpression(right object.Object) object.Object {
+	switch right := right.(type) {
+	case *object.Integer:
+		// -math.MinInt64 is out of the range of an int64.
+		if right.Big != nil || right.Value == math.MinInt64 {
+			return object.NewBigInteger(new(big.Int).Neg(right.BigInt()))
+		}
+		return &object.Integer{Value: -right.Value}
+	case *object.Float:
+		return &object.Float{Value: -right.Value}
+	default:
+		return newError("unknown operator: -%s", right.Type())
+	}
+}
+
+// evalInfixExpression applies the infix operator to the evaluated operands.
+// Numbers of different types can be mixed, the result of an integer and a float is a float, e.g. '1 + 0.5' is 1.5 and '1 == 1.0' is true.
+func evalInfixExpression(operator string, left, right object.Object) object.Object {
+	switch {
+	case left.Type() == object.INTEGER && right.Type() == object.INTEGER:
+		return evalIntegerInfixExpression(operator, left.(*object.Integer), right.(*object.Integer))
+	case isNumber(left) && isNumber(right):
+		return evalFloatInfixExpression(operator, left, right)
+	case left.Type() == object.STRING && right.Type() == object.STRING:
+		return evalStringInfixExpression(operator, left.(*object.String), right.(*object.String))
+	case left.Type() != right.Type():
+		return newError("type mismatch: %s %s %s", left.Type(), operator, right.Type())
+	// Booleans and null are shared objects, comparing the pointers is enough.
+	case operator == "==":
+		return nativeBoolToBooleanObject(left == right)
+	case operator == "!=":
+		return nativeBoolToBooleanObject(left != right)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// maxPowerBits bounds the size in bits of the result of '^' on integers, to the order of 300,000 decimal digits.
+// It keeps a script from running out of memory by mistake, e.g. '10 ^ 10 ^ 10'.
+const maxPowerBits = 1 << 20
+
+// evalIntegerInfixExpression applies the infix operator to two integers.
+// The arithmetic is exact: a result out of the range of an int64 is computed with math/big (see evalBigIntegerInfixExpression).
+func evalIntegerInfixExpression(operator string, left, right *object.Integer) object.Object {
+	if left.Big != nil || right.Big != nil {
+		return evalBigIntegerInfixExpression(operator, left, right)
+	}
+	l, r := left.Value, right.Value
+
+	switch operator {
+	case "+":
+		// The sum overflows if it doesn't have the sign of either operand.
+		if sum := l + r; (l^sum)&(r^sum) >= 0 {
+			return &object.Integer{Value: sum}
+		}
+	case "-":
+		// The difference overflows if the operands have different signs, and it doesn't have the sign of l.
+		if diff := l - r; (l^r)&(l^diff) >= 0 {
+			return &object.Integer{Value: diff}
+		}
+	case "*":
+		if product, ok := multiplyIntegers(l, r); ok {
+			return &object.Integer{Value: product}
+		}
+	case "/":
+		if r == 0 {
+			return newError("division by zero: %d / %d", l, r)
+		}
+		// The only quotient that overflows is math.MinInt64 / -1.
+		if l != math.MinInt64 || r != -1 {
+			return &object.Integer{Value: l / r}
+		}
+	case "^":
+		if r < 0 {
+			return newError("negative exponent: %d ^ %d", l, r)
+		}
+		if power, ok := integerPower(l, r); ok {
+			return &object.Integer{Value: power}
+		}
+	case "<":
+		return nativeBoolToBooleanObject(l < r)
+	case ">":
+		return nativeBoolToBooleanObject(l > r)
+	case "==":
+		return nativeBoolToBooleanObject(l == r)
+	case "!=":
+		return nativeBoolToBooleanObject(l != r)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+
+	// The result overflows an int64.
+	return evalBigIntegerInfixExpression(operator, left, right)
+}
+
+// evalBigIntegerInfixExpression applies the infix operator to two integers with math/big.
+// The result is held by an int64 again if it's in its range (see object.NewBigInteger).
+// Like for an int64, '/' truncates toward zero.
+func evalBigIntegerInfixExpression(operator string, left, right *object.Integer) object.Object {
+	l, r := left.BigInt(), right.BigInt()
+
+	switch operator {
+	case "+":
+		return object.NewBigInteger(new(big.Int).Add(l, r))
+	case "-":
+		return object.NewBigInteger(new(big.Int).Sub(l, r))
+	case "*":
+		return object.NewBigInteger(new(big.Int).Mul(l, r))
+	case "/":
+		if r.Sign() == 0 {
+			return newError("division by zero: %s / %s", left.Inspect(), right.Inspect())
+		}
+		return object.NewBigInteger(new(big.Int).Quo(l, r))
+	case "^":
+		if r.Sign() < 0 {
+			return newError("negative exponent: %s ^ %s", left.Inspect(), right.Inspect())
+		}
+		// 0, 1 and -1 stay small whatever the exponent, the result for a base of n bits has up to n * r bits.
+		// The bound is checked rather than the exact size, thus a power a little under the limit can be refused, but none above it is computed.
+		if l.CmpAbs(big.NewInt(1)) > 0 && (!r.IsInt64() || r.Int64() > maxPowerBits/int64(l.BitLen())) {
+			return newError("integer too large: %s ^ %s exceeds %d bits", left.Inspect(), right.Inspect(), maxPowerBits)
+		}
+		return object.NewBigInteger(new(big.Int).Exp(l, r, nil))
+	case "<":
+		return nativeBoolToBooleanObject(l.Cmp(r) < 0)
+	case ">":
+		return nativeBoolToBooleanObject(l.Cmp(r) > 0)
+	case "==":
+		return nativeBoolToBooleanObject(l.Cmp(r) == 0)
+	case "!=":
+		return nativeBoolToBooleanObject(l.Cmp(r) != 0)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// evalFloatInfixExpression applies the infix operator to two numbers, at least one of them is a float.
+// An integer operand is converted to the nearest float, so is it when compared, e.g. '9007199254740993 == 9007199254740992.0' is true.
+// Unlike integers, a float can be raised to a negative or fractional power, e.g. '2 ^ -1.0' is 0.5.
+func evalFloatInfixExpression(operator string, left, right object.Object) object.Object {
+	l, r := toFloat(left), toFloat(right)
+
+	switch operator {
+	case "+":
+		return &object.Float{Value: l + r}
+	case "-":
+		return &object.Float{Value: l - r}
+	case "*":
+		return &object.Float{Value: l * r}
+	case "/":
+		if r == 0 {
+			return newError("division by zero: %s / %s", left.Inspect(), right.Inspect())
+		}
+		return &object.Float{Value: l / r}
+	case "^":
+		return &object.Float{Value: math.Pow(l, r)}
+	case "<":
+		return nativeBoolToBooleanObject(l < r)
+	case ">":
+		return nativeBoolToBooleanObject(l > r)
+	case "==":
+		return nativeBoolToBooleanObject(l == r)
+	case "!=":
+		return nativeBoolToBooleanObject(l != r)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// isNumber reports whether obj is an integer or a float.
+func isNumber(obj object.Object) bool {
+	return obj.Type() == object.INTEGER || obj.Type() == object.FLOAT
+}
+
+// toFloat converts a number to a float64, obj must be an integer or a float.
+func toFloat(obj object.Object) float64 {
+	if integer, ok := obj.(*object.Integer); ok {
+		if integer.Big != nil {
+			f, _ := new(big.Float).SetInt(integer.Big).Float64()
+			return f
+		}
+		return float64(integer.Value)
+	}
+	return obj.(*object.Float).Value
+}
+
+// evalStringInfixExpression applies the infix operator to two strings.
+// '+' concatenates them.
+func evalStringInfixExpression(operator string, left, right *object.String) object.Object {
+	switch operator {
+	case "+":
+		return &object.String{Value: left.Value + right.Value}
+	case "==":
+		return nativeBoolToBooleanObject(left.Value == right.Value)
+	case "!=":
+		return nativeBoolToBooleanObject(left.Value != right.Value)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// integerPower computes base ^ exp by squaring, exp should be non-negative.
+// It reports whether the result is in the range of an int64.
+func integerPower(base, exp int64) (int64, bool) {
+	result := int64(1)
+	for exp > 0 {
+		var ok bool
+		if exp&1 == 1 {
+			if result, ok = multiplyIntegers(result, base); !ok {
+				return 0, false
+			}
+		}
+		exp >>= 1
+		// The square is only needed for the next bit, it can overflow even if the result doesn't.
+		if exp > 0 {
+			if base, ok = multiplyIntegers(base, base); !ok {
+				return 0, false
+			}
+		}
+	}
+	return result, true
+}
+
+// multiplyIntegers returns a * b, and reports whether it's in the range of an int64.
+func multiplyIntegers(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	product := a * b
+	// Dividing back catches every overflow but math.MinInt64 * -1, since math.MinInt64 / -1 overflows as well.
+	if product/b != a || (a == math.MinInt64 && b == -1) || (a == -1 && b == math.MinInt64) {
+		return 0, false
+	}
+	return product, true
+}
+
+// isTruthy determines whether obj counts as true in a condition.
+// Only false and null are falsy, everything else is truthy.
+func isTruthy(obj object.Object) bool {
+	switch obj {
+	case FALSE, NULL:
+		return false
+	default:
+		return true
+	}
+}
+
+// nativeBoolToBooleanObject turns a Go bool to one of the shared boolean objects.
+func nativeBoolToBooleanObject(b bool) *object.Boolean {
+	if b {
+		return TRUE
+	}
+	return FALSE
+}
+
+func newError(format string, a ...any) *object.Error {
+	return &object.Error{Message: fmt.Sprintf(format, a...)}
+}
+
+func isError(obj object.Object) bool {
+	return obj != nil && obj.Type() == object.ERROR
+}
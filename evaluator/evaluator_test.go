@@ -0,0 +1,546 @@
+package evaluator
+
+import (
+	"Lisa/lexer"
+	"Lisa/object"
+	"Lisa/parser"
+	"testing"
+)
+
+func testEval(t *testing.T, input string) object.Object {
+	t.Helper()
+	l := lexer.New(input)
+	p := parser.New(l)
+	astRoot := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("error parsing %q: %v", input, p.Errors())
+	}
+	return Eval(astRoot, NewEnvironment())
+}
+
+func TestEval_IntegerExpression(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected int64
+	}{
+		{"5;", 5},
+		{"10;", 10},
+		{"-5;", -5},
+		{"--10;", 10},
+		{"5 + 5 + 5 + 5 - 10;", 10},
+		{"2 * 2 * 2 * 2 * 2;", 32},
+		{"-50 + 100 + -50;", 0},
+		{"5 * 2 + 10;", 20},
+		{"5 + 2 * 10;", 25},
+		{"50 / 2 * 2 + 10;", 60},
+		{"2 * (5 + 10);", 30},
+		{"(5 + 10 * 2 + 15 / 3) * 2 + -10;", 50},
+		{"2 ^ 10;", 1024},
+		{"2 ^ 3 ^ 2;", 512},
+		{"-2 ^ 2;", -4},
+		{"(-2) ^ 2;", 4},
+		{"0xFF + 0o755 + 0b1010;", 255 + 493 + 10},
+		{"1_000_000 / 1_000;", 1000},
+	}
+
+	for i, tc := range testCases {
+		correctIntegerObject(t, i, testEval(t, tc.input), tc.expected)
+	}
+}
+
+func TestEval_BigIntegerExpression(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+		// expectedBig is whether the result is out of the range of an int64, and thus held by Big.
+		expectedBig bool
+	}{
+		{"9223372036854775807 + 1;", "9223372036854775808", true},
+		{"-9223372036854775807 - 1 - 1;", "-9223372036854775809", true},
+		{"4294967296 * 4294967296;", "18446744073709551616", true},
+		{"-4294967296 * 4294967296 * 2;", "-36893488147419103232", true},
+		{"(-9223372036854775807 - 1) / -1;", "9223372036854775808", true},
+		{"-(-9223372036854775807 - 1);", "9223372036854775808", true},
+		{"2 ^ 64;", "18446744073709551616", true},
+		{"3 ^ 40 ^ 1;", "12157665459056928801", true},
+		{"123456789012345678901234567890;", "123456789012345678901234567890", true},
+		{"123456789012345678901234567890 + 0x1_0000_0000_0000_0000;", "123456789030792422974944119506", true},
+		{"var fact = fn(n) { if (n < 2) { return 1; } n * fact(n - 1); }; fact(25);", "15511210043330985984000000", true},
+		// A result in the range of an int64 is demoted.
+		{"(9223372036854775807 + 1) - 1;", "9223372036854775807", false},
+		{"2 ^ 100 / 2 ^ 99;", "2", false},
+		{"18446744073709551616 - 18446744073709551615;", "1", false},
+		{"-(2 ^ 63);", "-9223372036854775808", false},
+		{"(2 ^ 63) * 0;", "0", false},
+		// '/' truncates toward zero.
+		{"-(2 ^ 64) / 3;", "-6148914691236517205", false},
+		{"(2 ^ 64 + 2) / -4;", "-4611686018427387904", false},
+		{"1 ^ (2 ^ 64);", "1", false},
+		{"(-1) ^ (2 ^ 64 + 1);", "-1", false},
+	}
+
+	for i, tc := range testCases {
+		evaluated := testEval(t, tc.input)
+		integer, ok := evaluated.(*object.Integer)
+		if !ok {
+			t.Errorf("tests[%d] - error object type: expected *object.Integer, got %T (%+v).\n", i, evaluated, evaluated)
+			continue
+		}
+		if integer.Inspect() != tc.expected {
+			t.Errorf("tests[%d] - error integer value: expected %s, got %s.\n", i, tc.expected, integer.Inspect())
+		}
+		if (integer.Big != nil) != tc.expectedBig {
+			t.Errorf("tests[%d] - error integer held by Big: expected %t, got %t.\n", i, tc.expectedBig, integer.Big != nil)
+		}
+	}
+
+	comparisons := []struct {
+		input    string
+		expected bool
+	}{
+		{"2 ^ 64 > 2 ^ 63;", true},
+		{"2 ^ 64 < 1;", false},
+		{"-(2 ^ 64) < 1;", true},
+		{"2 ^ 64 == 18446744073709551616;", true},
+		{"2 ^ 64 == 2 ^ 64 + 1;", false},
+		{"2 ^ 64 != 2 ^ 64 + 1;", true},
+		{"2 ^ 64 > 1.5;", true},
+		{"2 ^ 64 == 18446744073709551616.0;", true},
+	}
+
+	for i, tc := range comparisons {
+		correctBooleanObject(t, i, testEval(t, tc.input), tc.expected)
+	}
+
+	evaluated := testEval(t, `{2 ^ 64: "big", 2 ^ 63 - 1: "max"}[18446744073709551616];`)
+	if str, ok := evaluated.(*object.String); !ok || str.Value != "big" {
+		t.Errorf("error indexing hash with a big integer: expected %q, got %+v.\n", "big", evaluated)
+	}
+	evaluated = testEval(t, "2 ^ 64 * 0.5;")
+	if f, ok := evaluated.(*object.Float); !ok || f.Value != 9223372036854775808 {
+		t.Errorf("error multiplying a big integer by a float: expected 9.223372036854776e+18, got %+v.\n", evaluated)
+	}
+}
+
+func TestEval_FloatExpression(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected float64
+	}{
+		{"3.14;", 3.14},
+		{"1.5e-3;", 0.0015},
+		{"-2.5;", -2.5},
+		{"0.5 + 0.25;", 0.75},
+		{"1 + 0.5;", 1.5},
+		{"0.5 + 1;", 1.5},
+		{"3 - 0.5;", 2.5},
+		{"2 * 1.5;", 3},
+		{"1 / 4.0;", 0.25},
+		{"7.5 / 2;", 3.75},
+		{"2 ^ 0.5 ^ 2;", 1.189207115002721},
+		{"2 ^ -1.0;", 0.5},
+		{"4.0 ^ 2;", 16},
+		{"(1 + 2) * 0.5;", 1.5},
+		{"var half = fn(x) { x / 2.0; }; half(5);", 2.5},
+	}
+
+	for i, tc := range testCases {
+		evaluated := testEval(t, tc.input)
+		result, ok := evaluated.(*object.Float)
+		if !ok {
+			t.Errorf("tests[%d] - error object type: expected *object.Float, got %T (%+v).\n", i, evaluated, evaluated)
+			continue
+		}
+		if result.Value != tc.expected {
+			t.Errorf("tests[%d] - error float value: expected %g, got %g.\n", i, tc.expected, result.Value)
+		}
+	}
+
+	// The integer arithmetic stays integer.
+	correctIntegerObject(t, 0, testEval(t, "7 / 2;"), 3)
+}
+
+func TestEval_BooleanExpression(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected bool
+	}{
+		{"true;", true},
+		{"false;", false},
+		{"!true;", false},
+		{"!false;", true},
+		{"!5;", false},
+		{"!!true;", true},
+		{"!!5;", true},
+		{"1 < 2;", true},
+		{"1 > 2;", false},
+		{"1 == 1;", true},
+		{"1 != 1;", false},
+		{"true == true;", true},
+		{"true != false;", true},
+		{"(1 < 2) == true;", true},
+		{"(1 > 2) == true;", false},
+		{"1 == 1.0;", true},
+		{"1.0 != 1;", false},
+		{"0.1 + 0.2 == 0.3;", false},
+		{"1 < 1.5;", true},
+		{"2.5 > 3;", false},
+		{"!0.0;", false},
+		{"9007199254740993 == 9007199254740992.0;", true},
+	}
+
+	for i, tc := range testCases {
+		correctBooleanObject(t, i, testEval(t, tc.input), tc.expected)
+	}
+}
+
+func TestEval_ReturnStatement(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected int64
+	}{
+		{"return 10;", 10},
+		{"return 10; 9;", 10},
+		{"return 2 * 5; 9;", 10},
+		{"9; return 2 * 5; 9;", 10},
+	}
+
+	for i, tc := range testCases {
+		correctIntegerObject(t, i, testEval(t, tc.input), tc.expected)
+	}
+}
+
+func TestEval_VarStatement(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected int64
+	}{
+		{"var a = 5; a;", 5},
+		{"var a = 5 * 5; a;", 25},
+		{"var a = 5; var b = a; b;", 5},
+		{"var a = 5; var b = a; var c = a + b + 5; c;", 15},
+		{"var a = 5; -a;", -5},
+	}
+
+	for i, tc := range testCases {
+		correctIntegerObject(t, i, testEval(t, tc.input), tc.expected)
+	}
+}
+
+func TestEval_StringExpression(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{`"hello world";`, "hello world"},
+		{`"hello" + " " + "world";`, "hello world"},
+		{`var greet = fn(name) { "hello, " + name + "\n"; }; greet("Lisa");`, "hello, Lisa\n"},
+	}
+
+	for i, tc := range testCases {
+		evaluated := testEval(t, tc.input)
+		str, ok := evaluated.(*object.String)
+		if !ok {
+			t.Errorf("tests[%d] - error object type: expected *object.String, got %T (%+v).\n", i, evaluated, evaluated)
+			continue
+		}
+		if str.Value != tc.expected {
+			t.Errorf("tests[%d] - error string value: expected %q, got %q.\n", i, tc.expected, str.Value)
+		}
+	}
+
+	correctBooleanObject(t, 0, testEval(t, `"a" + "b" == "ab";`), true)
+	correctBooleanObject(t, 1, testEval(t, `"a" != "a";`), false)
+}
+
+func TestEval_IfExpression(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected any
+	}{
+		{"if (true) { 10; }", int64(10)},
+		{"if (false) { 10; }", nil},
+		{"if (1) { 10; }", int64(10)},
+		{"if (1 < 2) { 10; }", int64(10)},
+		{"if (1 > 2) { 10; }", nil},
+		{"if (1 > 2) { 10; } else { 20; }", int64(20)},
+		{"if (1 < 2) { 10; } else { 20; }", int64(10)},
+		{"var x = if (1 < 2) { 10; } else { 20; }; x * 2;", int64(20)},
+		{"if (10 > 1) { if (10 > 1) { return 10; } return 1; }", int64(10)},
+	}
+
+	for i, tc := range testCases {
+		evaluated := testEval(t, tc.input)
+		if integer, ok := tc.expected.(int64); ok {
+			correctIntegerObject(t, i, evaluated, integer)
+		} else if evaluated != NULL {
+			t.Errorf("tests[%d] - error object: expected NULL, got %T (%+v).\n", i, evaluated, evaluated)
+		}
+	}
+}
+
+func TestEval_FunctionApplication(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected int64
+	}{
+		{"var identity = fn(x) { x; }; identity(5);", 5},
+		{"var identity = fn(x) { return x; }; identity(5);", 5},
+		{"var double = fn(x) { x * 2; }; double(5);", 10},
+		{"var add = fn(x, y) { x + y; }; add(5, 5);", 10},
+		{"var add = fn(x, y) { x + y; }; add(5 + 5, add(5, 5));", 20},
+		{"fn(x) { x; }(5);", 5},
+		{"var max = fn(a, b) { if (a > b) { return a; } return b; }; max(3, 7);", 7},
+		{"var fact = fn(n) { if (n < 2) { return 1; } n * fact(n - 1); }; fact(5);", 120},
+		// The depth is the one of the callers, a closure defined at the top level is still nested in its caller.
+		{"var count = fn(n) { if (n == 0) { return 0; } 1 + count(n - 1); }; count(9999);", 9999},
+	}
+
+	for i, tc := range testCases {
+		correctIntegerObject(t, i, testEval(t, tc.input), tc.expected)
+	}
+}
+
+func TestEval_Closures(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected int64
+	}{
+		{"var newAdder = fn(x) { fn(y) { x + y; }; }; var addTwo = newAdder(2); addTwo(3);", 5},
+		{"var apply = fn(f, x) { f(x); }; apply(fn(x) { x * x; }, 4);", 16},
+		{"var x = 10; var f = fn() { var x = 1; x; }; f() + x;", 11},
+	}
+
+	for i, tc := range testCases {
+		correctIntegerObject(t, i, testEval(t, tc.input), tc.expected)
+	}
+}
+
+func TestEval_ArrayLiteral(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"[];", "[]"},
+		{"[1, 2 * 2, 3 + 3];", "[1, 4, 6]"},
+		{`var x = 1; [x, "two", [x + 2]];`, "[1, two, [3]]"},
+		{"[fn(x) { x; }(1), if (true) { 2; }];", "[1, 2]"},
+	}
+
+	for i, tc := range testCases {
+		evaluated := testEval(t, tc.input)
+		array, ok := evaluated.(*object.Array)
+		if !ok {
+			t.Errorf("tests[%d] - error object type: expected *object.Array, got %T (%+v).\n", i, evaluated, evaluated)
+			continue
+		}
+		if array.Inspect() != tc.expected {
+			t.Errorf("tests[%d] - error array: expected %q, got %q.\n", i, tc.expected, array.Inspect())
+		}
+	}
+}
+
+func TestEval_IndexExpression(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected int64
+	}{
+		{"[1, 2, 3][0];", 1},
+		{"[1, 2, 3][2];", 3},
+		{"var i = 0; [1][i];", 1},
+		{"[1, 2, 3][1 + 1];", 3},
+		{"var arr = [1, 2, 3]; arr[0] + arr[1] + arr[2];", 6},
+		{"var arr = [1, 2, 3]; var i = arr[0]; arr[i];", 2},
+		{"[1, 2, 3][-1];", 3},
+		{"[1, 2, 3][-3];", 1},
+		{"[[1, 2], [3, 4]][1][0];", 3},
+		{"var first = fn(arr) { arr[0]; }; first([7, 8]);", 7},
+	}
+
+	for i, tc := range testCases {
+		correctIntegerObject(t, i, testEval(t, tc.input), tc.expected)
+	}
+}
+
+func TestEval_SliceExpression(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"[1, 2, 3, 4][1:3];", "[2, 3]"},
+		{"[1, 2, 3, 4][:2];", "[1, 2]"},
+		{"[1, 2, 3, 4][2:];", "[3, 4]"},
+		{"[1, 2, 3, 4][:];", "[1, 2, 3, 4]"},
+		{"[1, 2, 3, 4][-2:];", "[3, 4]"},
+		{"[1, 2, 3, 4][:-1];", "[1, 2, 3]"},
+		{"[1, 2, 3, 4][-3:-1];", "[2, 3]"},
+		{"[1, 2, 3, 4][2:2];", "[]"},
+		{"[1, 2, 3, 4][4:];", "[]"},
+		{"var arr = [1, 2, 3]; var i = 1; arr[i:i + 1];", "[2]"},
+		{"[1, 2, 3, 4][1:][1:];", "[3, 4]"},
+	}
+
+	for i, tc := range testCases {
+		evaluated := testEval(t, tc.input)
+		array, ok := evaluated.(*object.Array)
+		if !ok {
+			t.Errorf("tests[%d] - error object type: expected *object.Array, got %T (%+v).\n", i, evaluated, evaluated)
+			continue
+		}
+		if array.Inspect() != tc.expected {
+			t.Errorf("tests[%d] - error array: expected %q, got %q.\n", i, tc.expected, array.Inspect())
+		}
+	}
+}
+
+func TestEval_HashLiteral(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"{};", "{}"},
+		{`var two = "two"; {"one": 10 - 9, two: 1 + 1, "thr" + "ee": 6 / 2, 4: 4, true: 5, false: 6};`, "{one: 1, two: 2, three: 3, 4: 4, true: 5, false: 6}"},
+		// A repeated key takes the last value.
+		{`{"a": 1, "b": 2, "a": 3};`, "{a: 3, b: 2}"},
+		{`{"a": [1, 2], "b": {"c": 3}};`, "{a: [1, 2], b: {c: 3}}"},
+		// 2 ^ 64 and 554774489934347788 had the same key when the one of a big integer was a 64-bit hash.
+		{`{554774489934347788: "small", 2 ^ 64: "big"};`, "{554774489934347788: small, 18446744073709551616: big}"},
+	}
+
+	for i, tc := range testCases {
+		evaluated := testEval(t, tc.input)
+		hash, ok := evaluated.(*object.Hash)
+		if !ok {
+			t.Errorf("tests[%d] - error object type: expected *object.Hash, got %T (%+v).\n", i, evaluated, evaluated)
+			continue
+		}
+		if hash.Inspect() != tc.expected {
+			t.Errorf("tests[%d] - error hash: expected %q, got %q.\n", i, tc.expected, hash.Inspect())
+		}
+	}
+}
+
+func TestEval_HashIndexExpression(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected any
+	}{
+		{`{"foo": 5}["foo"];`, int64(5)},
+		{`{"foo": 5}["bar"];`, nil},
+		{`var key = "foo"; {"foo": 5}[key];`, int64(5)},
+		{`{}["foo"];`, nil},
+		{"{5: 5}[5];", int64(5)},
+		{"{true: 5}[true];", int64(5)},
+		{"{false: 5}[false];", int64(5)},
+		{"{1: 5}[true];", nil},
+		{"{18446744073709551616: 5}[2 ^ 64];", int64(5)},
+		{"{2 ^ 64: 5}[554774489934347788];", nil},
+		{`var h = {"a": {"b": 7}}; h["a"]["b"];`, int64(7)},
+		{`var h = {"f": fn(x) { x * 2; }}; h["f"](4);`, int64(8)},
+	}
+
+	for i, tc := range testCases {
+		evaluated := testEval(t, tc.input)
+		if integer, ok := tc.expected.(int64); ok {
+			correctIntegerObject(t, i, evaluated, integer)
+		} else if evaluated != NULL {
+			t.Errorf("tests[%d] - error object: expected NULL, got %T (%+v).\n", i, evaluated, evaluated)
+		}
+	}
+}
+
+func TestEval_Errors(t *testing.T) {
+	testCases := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"-true;", "unknown operator: -BOOLEAN"},
+		{"foobar;", "identifier not found: foobar"},
+		{"-true; 5;", "unknown operator: -BOOLEAN"},
+		{"5 + true;", "type mismatch: INTEGER + BOOLEAN"},
+		{"true + false;", "unknown operator: BOOLEAN + BOOLEAN"},
+		{"5 / 0;", "division by zero: 5 / 0"},
+		{"2 ^ -1;", "negative exponent: 2 ^ -1"},
+		{"1.5 / 0;", "division by zero: 1.5 / 0"},
+		{"1 / 0.0;", "division by zero: 1 / 0.0"},
+		{"1.5 + true;", "type mismatch: FLOAT + BOOLEAN"},
+		{`"a" + 1.5;`, "type mismatch: STRING + FLOAT"},
+		{"-true + 1.5;", "unknown operator: -BOOLEAN"},
+		{"{1.5: 1};", "unusable as hash key: FLOAT"},
+		{"[1, 2][1.0];", "index must be an integer: got FLOAT"},
+		{"2 ^ 64 / 0;", "division by zero: 18446744073709551616 / 0"},
+		{"(2 ^ 64) ^ -1;", "negative exponent: 18446744073709551616 ^ -1"},
+		{"2 ^ (2 ^ 64);", "integer too large: 2 ^ 18446744073709551616 exceeds 1048576 bits"},
+		{"10 ^ 10 ^ 10;", "integer too large: 10 ^ 10000000000 exceeds 1048576 bits"},
+		// 3 ^ 1048576 has about 1.66 million bits, even though 3 is a base of 2 bits.
+		{"3 ^ 1048576;", "integer too large: 3 ^ 1048576 exceeds 1048576 bits"},
+		{"(-3) ^ 524289;", "integer too large: -3 ^ 524289 exceeds 1048576 bits"},
+		{"2 ^ 64 + true;", "type mismatch: INTEGER + BOOLEAN"},
+		{"[1][2 ^ 64];", "index out of range: 18446744073709551616 with length 1"},
+		{"[1][-(2 ^ 64)];", "index out of range: -18446744073709551616 with length 1"},
+		{"[1][2 ^ 64:];", "slice bound out of range: 18446744073709551616"},
+		{"if (10 > 1) { true + false; }", "unknown operator: BOOLEAN + BOOLEAN"},
+		{"var f = fn(x) { x; }; f(1, 2);", "wrong number of arguments: expected 1, got 2"},
+		{"var x = 5; x(1);", "not a function: INTEGER"},
+		{"var f = fn(n) { f(n + 1); }; f(0);", "maximum recursion depth exceeded"},
+		{"var f = fn(n) { if (n > 0) { 1 + f(n - 1); } else { 0; } }; f(20000);", "maximum recursion depth exceeded"},
+		{`"hello" - "world";`, "unknown operator: STRING - STRING"},
+		{`"hello" + 5;`, "type mismatch: STRING + INTEGER"},
+		{"var f = fn(x) { x; }; f(y);", "identifier not found: y"},
+		{"if (10 > 1) { if (10 > 1) { return true + false; } return 1; }", "unknown operator: BOOLEAN + BOOLEAN"},
+		{"[1, 2, 3][3];", "index out of range: 3 with length 3"},
+		{"[1, 2, 3][-4];", "index out of range: -4 with length 3"},
+		{"[][0];", "index out of range: 0 with length 0"},
+		{`[1, 2, 3]["1"];`, "index must be an integer: got STRING"},
+		{"5[0];", "index operator not supported: INTEGER"},
+		{"[1, 2, 3][1:4];", "slice bounds out of range: [1:4] with length 3"},
+		{"[1, 2, 3][2:1];", "slice bounds out of range: [2:1] with length 3"},
+		{"[1, 2, 3][-4:];", "slice bounds out of range: [-4:3] with length 3"},
+		{"[1, 2, 3][true:];", "slice bound must be an integer: got BOOLEAN"},
+		{`"abc"[1:];`, "slice operator not supported: STRING"},
+		{"[1, x];", "identifier not found: x"},
+		{"[1, 2][x];", "identifier not found: x"},
+		{`{"name": "Lisa"}[fn(x) { x; }];`, "unusable as hash key: FUNCTION"},
+		{`{fn(x) { x; }: "Lisa"};`, "unusable as hash key: FUNCTION"},
+		{`{[1]: 1};`, "unusable as hash key: ARRAY"},
+		{`{"a": 1}[{}];`, "unusable as hash key: HASH"},
+		{`{"a": x};`, "identifier not found: x"},
+		{`{"a": 1}[1:];`, "slice operator not supported: HASH"},
+	}
+
+	for i, tc := range testCases {
+		evaluated := testEval(t, tc.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("tests[%d] - error object type: expected *object.Error, got %T (%+v).\n", i, evaluated, evaluated)
+			continue
+		}
+		if errObj.Message != tc.expectedMessage {
+			t.Errorf("tests[%d] - error message: expected %q, got %q.\n", i, tc.expectedMessage, errObj.Message)
+		}
+	}
+}
+
+func correctIntegerObject(t *testing.T, i int, obj object.Object, expected int64) {
+	t.Helper()
+	result, ok := obj.(*object.Integer)
+	if !ok {
+		t.Errorf("tests[%d] - error object type: expected *object.Integer, got %T (%+v).\n", i, obj, obj)
+		return
+	}
+	if result.Value != expected {
+		t.Errorf("tests[%d] - error integer value: expected %d, got %d.\n", i, expected, result.Value)
+	}
+}
+
+func correctBooleanObject(t *testing.T, i int, obj object.Object, expected bool) {
+	t.Helper()
+	result, ok := obj.(*object.Boolean)
+	if !ok {
+		t.Errorf("tests[%d] - error object type: expected *object.Boolean, got %T (%+v).\n", i, obj, obj)
+		return
+	}
+	if result.Value != expected {
+		t.Errorf("tests[%d] - error boolean value: expected %t, got %t.\n", i, expected, result.Value)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"Lisa/format"
+	"Lisa/internal/diff"
+	"bytes"
+	"io"
+	"os"
+)
+
+// runFmt implements 'lisa fmt [-d] [-e program | files]', which formats Lisa source files to their canonical form.
+// The files are rewritten in place, or with -d, the differences are printed instead.
+// The source read from stdin (without files, or with '-') or given with -e is formatted to stdout.
+func runFmt(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := newFlagSet("fmt", "[-d] [-e program | files]", stderr)
+	printDiff := flags.Bool("d", false, "print the differences instead of rewriting the files")
+	expr := flags.String("e", "", "format `program` instead of files")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if isFlagSet(flags, "e") {
+		sources, code := readSources(flags, expr, true, stdin, stderr)
+		if code != exitOK {
+			return code
+		}
+		if err := formatSource(sources[0].name, sources[0].src, *printDiff, stdout); err != nil {
+			printError(stderr, err)
+			return exitCodeOf(err)
+		}
+		return exitOK
+	}
+
+	filenames := flags.Args()
+	if len(filenames) == 0 {
+		filenames = []string{"-"}
+	}
+	exitCode := exitOK
+	for _, filename := range filenames {
+		var err error
+		if filename == "-" {
+			var src []byte
+			if src, err = io.ReadAll(stdin); err == nil {
+				err = formatSource("<stdin>", src, *printDiff, stdout)
+			}
+		} else {
+			err = formatFile(filename, *printDiff, stdout)
+		}
+		if err != nil {
+			printError(stderr, err)
+			exitCode = max(exitCode, exitCodeOf(err))
+		}
+	}
+	return exitCode
+}
+
+// formatFile formats the file in place, or prints the differences to out if printDiff is set.
+// A file that's already formatted isn't touched.
+func formatFile(filename string, printDiff bool, out io.Writer) error {
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	formatted, err := format.Source(filename, src)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(src, formatted) {
+		return nil
+	}
+
+	if printDiff {
+		_, err = out.Write(diff.Diff(filename+".orig", src, filename, formatted))
+		return err
+	}
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, formatted, info.Mode().Perm())
+}
+
+// formatSource writes the formatted src to out, or the differences if printDiff is set.
+func formatSource(name string, src []byte, printDiff bool, out io.Writer) error {
+	formatted, err := format.Source(name, src)
+	if err != nil {
+		return err
+	}
+	if printDiff {
+		formatted = diff.Diff(name+".orig", src, name, formatted)
+	}
+	_, err = out.Write(formatted)
+	return err
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunFmt(t *testing.T) {
+	dir := t.TempDir()
+	unformatted := filepath.Join(dir, "unformatted.lisa")
+	formatted := filepath.Join(dir, "formatted.lisa")
+	broken := filepath.Join(dir, "broken.lisa")
+	files := map[string]string{
+		unformatted: "var x=1;  // One.\nx+2;",
+		formatted:   "var x = 1; // One.\nx + 2;\n",
+		broken:      "var x = ;",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// With -d, the files are left untouched and the differences are printed.
+	var stdout, stderr bytes.Buffer
+	if code := runFmt([]string{"-d", unformatted, formatted}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("error exit code of fmt -d: expected 0, got %d (%s).\n", code, stderr.String())
+	}
+	expectedDiff := "--- " + unformatted + ".orig\n+++ " + unformatted + "\n@@ -1,2 +1,2 @@\n-var x=1;  // One.\n-x+2;\n\\ No newline at end of file\n+var x = 1; // One.\n+x + 2;\n"
+	if stdout.String() != expectedDiff {
+		t.Errorf("error output of fmt -d: expected %q, got %q.\n", expectedDiff, stdout.String())
+	}
+	if content, _ := os.ReadFile(unformatted); string(content) != files[unformatted] {
+		t.Errorf("error file after fmt -d: expected %q, got %q.\n", files[unformatted], content)
+	}
+
+	// Without -d, the files are rewritten in place, the broken one is reported.
+	stdout.Reset()
+	stderr.Reset()
+	if code := runFmt([]string{unformatted, broken, formatted}, nil, &stdout, &stderr); code != exitSyntaxError {
+		t.Errorf("error exit code of fmt: expected %d, got %d.\n", exitSyntaxError, code)
+	}
+	if content, _ := os.ReadFile(unformatted); string(content) != files[formatted] {
+		t.Errorf("error file after fmt: expected %q, got %q.\n", files[formatted], content)
+	}
+	if content, _ := os.ReadFile(broken); string(content) != files[broken] {
+		t.Errorf("error broken file after fmt: expected %q, got %q.\n", files[broken], content)
+	}
+	if !strings.HasPrefix(stderr.String(), broken+":1:9: ") {
+		t.Errorf("error message of fmt: expected the position of the error, got %q.\n", stderr.String())
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("error output of fmt: expected nothing, got %q.\n", stdout.String())
+	}
+}
+
+func TestRunFmt_Stdin(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := runFmt(nil, strings.NewReader("return   fn(a,b){a*b;};"), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("error exit code: expected 0, got %d (%s).\n", code, stderr.String())
+	}
+	expected := "return fn(a, b) {\n\ta * b;\n};\n"
+	if stdout.String() != expected {
+		t.Errorf("error output: expected %q, got %q.\n", expected, stdout.String())
+	}
+}
@@ -0,0 +1,418 @@
+// Package format implements the canonical formatting of Lisa source code.
+//
+// The canonical form has one statement per line, blocks indented with a tab, a space around infix operators and after commas,
+// and only the parentheses the precedence of operators requires.
+// Comments are kept: a comment on the same line as the code before it stays there, the others get a line of their own.
+// A comment inside an expression stays between the same code, the line is broken after it if the code goes on on a later line, e.g. after a line comment.
+// At most one blank line between statements is kept.
+package format
+
+import (
+	"Lisa/ast"
+	token "Lisa/lexToken"
+	"Lisa/lexer"
+	"Lisa/parser"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// Precedences of the expressions, they mirror the ones of the parser.
+// operand is for the expressions that never need parentheses, e.g. literals.
+const (
+	_ int = iota
+	lowest
+	equals
+	lessGreater
+	sum
+	product
+	prefix
+	exponent
+	call
+	index
+	operand
+)
+
+var infixPrecedences = map[string]int{
+	"==": equals,
+	"!=": equals,
+	"<":  lessGreater,
+	">":  lessGreater,
+	"+":  sum,
+	"-":  sum,
+	"*":  product,
+	"/":  product,
+	"^":  exponent,
+}
+
+// Source formats the Lisa source code src, filename is used for the positions of the errors.
+// The source code must be free of syntax errors, otherwise the parser.ErrorList is returned.
+func Source(filename string, src []byte) ([]byte, error) {
+	p := parser.New(lexer.NewFile(filename, string(src)))
+	root := p.ParseProgram()
+	if err := p.ParseErrors().Err(); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := Node(&buf, root); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Node writes the canonical form of root, along with its comments, to w.
+// It fails if the tree contains an *ast.BadStatement or an *ast.BadExpression.
+func Node(w io.Writer, root *ast.ProgramRoot) error {
+	p := &printer{comments: root.Comments}
+	p.statements(root.Statements)
+	// The comments after the last statement.
+	p.flushComments(math.MaxInt)
+	if p.err != nil {
+		return p.err
+	}
+
+	if p.buf.Len() > 0 {
+		p.buf.WriteByte('\n')
+	}
+	_, err := w.Write(p.buf.Bytes())
+	return err
+}
+
+// printer writes the canonical form of a tree to buf.
+// The current line isn't ended until the next one starts, so a trailing comment can still be appended to it.
+type printer struct {
+	buf    bytes.Buffer
+	indent int
+	// comments are the comments not printed yet, in the order they appear.
+	comments []token.Comment
+	// lastLine is the line in the source of the last thing printed.
+	lastLine int
+	// afterLbrace is set right after a '{' is printed, a block doesn't start with a blank line.
+	afterLbrace bool
+	// afterComment is set right after a comment is printed, nothing else can go on the same line.
+	afterComment bool
+	// lineStart is set right after a line is indented, the code printed next doesn't start with a space.
+	lineStart bool
+	// leadingIf is set while printing a statement that starts with an if expression but isn't one, e.g. '(if (x) { f; })(1);'.
+	// The if expression is parenthesized, otherwise the statement would end at its '}'.
+	leadingIf bool
+	// err is the first error encountered.
+	err error
+}
+
+// newline ends the current line and indents the next one, line is the line in the source of what's printed next.
+// A blank line between them in the source is kept if blank is set.
+func (p *printer) newline(line int, blank bool) {
+	if p.buf.Len() > 0 {
+		p.buf.WriteByte('\n')
+		if blank && !p.afterLbrace && line > p.lastLine+1 {
+			p.buf.WriteByte('\n')
+		}
+	}
+	p.buf.WriteString(strings.Repeat("\t", p.indent))
+	p.afterLbrace = false
+	p.afterComment = false
+	p.lineStart = true
+}
+
+// flushComments prints the comments located before offset, between two statements.
+// A comment that starts on a line of the code printed last is appended to it, e.g. the comment in 'x; // note'.
+func (p *printer) flushComments(offset int) {
+	for len(p.comments) > 0 && p.comments[0].Span.Start.Offset < offset {
+		c := p.comments[0]
+		p.comments = p.comments[1:]
+
+		if p.buf.Len() > 0 && !p.afterComment && c.Span.Start.Line <= p.lastLine {
+			p.buf.WriteString(" ")
+		} else {
+			p.newline(c.Span.Start.Line, true)
+		}
+		p.buf.WriteString(c.Text)
+		p.lastLine = max(p.lastLine, c.Span.End.Line)
+		p.afterLbrace = false
+		p.afterComment = true
+		p.lineStart = false
+	}
+}
+
+// inlineComments prints the comments located before pos, where the code printed next is, in the middle of a statement.
+// A comment followed by the code on the same line stays on the line, followed by a space if spaceAfter is set, e.g. 'fn(/* p */ a)'.
+// Otherwise, e.g. after a line comment, the line is broken and the code goes on on the next one, indented once more.
+func (p *printer) inlineComments(pos token.Position, spaceAfter bool) {
+	for len(p.comments) > 0 && p.comments[0].Span.Start.Offset < pos.Offset {
+		c := p.comments[0]
+		p.comments = p.comments[1:]
+
+		if buf := p.buf.Bytes(); !p.lineStart && len(buf) > 0 && !strings.ContainsRune(" ([{", rune(buf[len(buf)-1])) {
+			p.buf.WriteString(" ")
+		}
+		p.buf.WriteString(c.Text)
+		p.lastLine = max(p.lastLine, c.Span.End.Line)
+		p.afterLbrace = false
+		p.lineStart = false
+
+		if strings.HasPrefix(c.Text, "//") || c.Span.End.Line < pos.Line {
+			p.indent++
+			p.newline(pos.Line, false)
+			p.indent--
+		} else if spaceAfter {
+			p.buf.WriteString(" ")
+		}
+	}
+}
+
+// mark records that the code at pos is printed.
+func (p *printer) mark(pos token.Position) {
+	p.lastLine = max(p.lastLine, pos.Line)
+}
+
+func (p *printer) print(s string) {
+	if p.lineStart {
+		s = strings.TrimLeft(s, " ")
+		p.lineStart = false
+	}
+	p.buf.WriteString(s)
+}
+
+func (p *printer) statements(stmts []ast.Statement) {
+	for _, stmt := range stmts {
+		p.flushComments(stmt.Pos().Offset)
+		p.newline(stmt.Pos().Line, true)
+		p.statement(stmt)
+
+		// A statement that starts with 'if' ends at the '}' of the if expression, the ';' after it is dropped.
+		if es, ok := stmt.(*ast.ExpressionStatement); ok {
+			if _, isIf := es.Expression.(*ast.IfExpression); isIf {
+				continue
+			}
+		}
+		p.print(";")
+	}
+}
+
+// startsWithIf reports whether the canonical form of exp starts with an if expression, e.g. 'if (x) { f; }(1)'.
+func startsWithIf(exp ast.Expression) bool {
+	switch e := exp.(type) {
+	case *ast.IfExpression:
+		return true
+	case *ast.InfixExpression:
+		leftPrec, _ := operandPrecedences(e)
+		return precedence(e.Left) >= leftPrec && startsWithIf(e.Left)
+	case *ast.CallExpression:
+		return precedence(e.Function) >= call && startsWithIf(e.Function)
+	case *ast.IndexExpression:
+		return precedence(e.Left) >= index && startsWithIf(e.Left)
+	case *ast.SliceExpression:
+		return precedence(e.Left) >= index && startsWithIf(e.Left)
+	default:
+		return false
+	}
+}
+
+// statement prints stmt without the ';' that ends it.
+func (p *printer) statement(stmt ast.Statement) {
+	p.mark(stmt.Pos())
+
+	switch s := stmt.(type) {
+	case *ast.VarStatement:
+		p.print("var ")
+		p.expression(s.Name, lowest)
+		if s.Value != nil {
+			p.print(" = ")
+			p.expression(s.Value, lowest)
+		}
+	case *ast.ReturnStatement:
+		p.print("return")
+		if s.ReturnValue != nil {
+			p.print(" ")
+			p.expression(s.ReturnValue, lowest)
+		}
+	case *ast.ExpressionStatement:
+		if _, isIf := s.Expression.(*ast.IfExpression); !isIf && startsWithIf(s.Expression) {
+			p.leadingIf = true
+		}
+		p.expression(s.Expression, lowest)
+	case *ast.BlockStatement:
+		p.block(s)
+	case *ast.BadStatement:
+		p.fail(s.Pos(), "a statement with syntax errors")
+	default:
+		p.fail(s.Pos(), fmt.Sprintf("unknown statement %T", s))
+	}
+}
+
+// block prints the braces and the statements in between, indented.
+func (p *printer) block(b *ast.BlockStatement) {
+	p.inlineComments(b.Pos(), true)
+	p.print("{")
+	p.mark(b.Pos())
+	p.afterLbrace = true
+
+	p.indent++
+	p.statements(b.Statements)
+	p.flushComments(b.Rbrace.Offset)
+	p.indent--
+
+	if !p.afterLbrace {
+		p.newline(b.Rbrace.Line, false)
+	}
+	p.afterLbrace = false
+	p.print("}")
+	p.mark(b.Rbrace)
+}
+
+// expression prints exp, in parentheses if it binds looser than the context it's printed in requires.
+// exp binds tight enough when its precedence is at least minPrecedence.
+func (p *printer) expression(exp ast.Expression, minPrecedence int) {
+	p.inlineComments(exp.Pos(), true)
+	p.mark(exp.Pos())
+
+	parenthesized := precedence(exp) < minPrecedence
+	// The leading if expression of a statement is the first expression printed in it.
+	if _, isIf := exp.(*ast.IfExpression); isIf && p.leadingIf {
+		parenthesized = true
+		p.leadingIf = false
+	}
+	if parenthesized {
+		p.print("(")
+	}
+
+	switch e := exp.(type) {
+	case *ast.IdentifierExpression:
+		p.print(e.Value)
+	case *ast.IntegerLiteralExpression:
+		// The literal is printed as it's written.
+		p.print(e.Token.Literal)
+	case *ast.FloatLiteral:
+		p.print(e.Token.Literal)
+	case *ast.StringLiteral:
+		// The string is printed as written, re-quoting the value would rewrite the escape sequences.
+		if e.Token.Raw != "" {
+			p.print(e.Token.Raw)
+		} else {
+			p.print(ast.Quote(e.Value))
+		}
+	case *ast.BooleanExpression:
+		p.print(e.Token.Literal)
+	case *ast.PrefixExpression:
+		p.print(e.Operator)
+		p.expression(e.RightToken, prefix)
+	case *ast.InfixExpression:
+		leftPrec, rightPrec := operandPrecedences(e)
+		p.expression(e.Left, leftPrec)
+		p.inlineComments(e.Token.Pos(), false)
+		p.print(" " + e.Operator + " ")
+		p.expression(e.Right, rightPrec)
+	case *ast.IfExpression:
+		p.print("if (")
+		p.expression(e.Condition, lowest)
+		p.print(") ")
+		p.block(e.Consequence)
+		if e.Alternative != nil {
+			// The comments between the '}' and the '{' are printed before 'else'.
+			p.inlineComments(e.Alternative.Pos(), false)
+			p.print(" else ")
+			p.block(e.Alternative)
+		}
+	case *ast.FunctionLiteral:
+		p.print("fn(")
+		for i, param := range e.Parameters {
+			if i > 0 {
+				p.print(", ")
+			}
+			p.inlineComments(param.Pos(), true)
+			p.print(param.Value)
+		}
+		p.print(") ")
+		p.block(e.Body)
+	case *ast.CallExpression:
+		p.expression(e.Function, call)
+		p.print("(")
+		for i, arg := range e.Arguments {
+			if i > 0 {
+				p.print(", ")
+			}
+			p.expression(arg, lowest)
+		}
+		p.print(")")
+	case *ast.ArrayLiteral:
+		p.print("[")
+		for i, element := range e.Elements {
+			if i > 0 {
+				p.print(", ")
+			}
+			p.expression(element, lowest)
+		}
+		p.print("]")
+	case *ast.HashLiteral:
+		p.print("{")
+		for i, pair := range e.Pairs {
+			if i > 0 {
+				p.print(", ")
+			}
+			p.expression(pair.Key, lowest)
+			p.print(": ")
+			p.expression(pair.Value, lowest)
+		}
+		p.print("}")
+	case *ast.IndexExpression:
+		p.expression(e.Left, index)
+		p.print("[")
+		p.expression(e.Index, lowest)
+		p.print("]")
+	case *ast.SliceExpression:
+		p.expression(e.Left, index)
+		p.print("[")
+		if e.Low != nil {
+			p.expression(e.Low, lowest)
+		}
+		p.print(":")
+		if e.High != nil {
+			p.expression(e.High, lowest)
+		}
+		p.print("]")
+	case *ast.BadExpression:
+		p.fail(e.Pos(), "an expression with syntax errors")
+	default:
+		p.fail(e.Pos(), fmt.Sprintf("unknown expression %T", e))
+	}
+
+	if parenthesized {
+		p.print(")")
+	}
+}
+
+// precedence returns how tight exp binds, which decides whether it needs parentheses.
+func precedence(exp ast.Expression) int {
+	switch e := exp.(type) {
+	case *ast.InfixExpression:
+		if prec, ok := infixPrecedences[e.Operator]; ok {
+			return prec
+		}
+		return lowest
+	case *ast.PrefixExpression:
+		return prefix
+	default:
+		return operand
+	}
+}
+
+// operandPrecedences returns the minimum precedences of the operands of an infix expression.
+// '^' is right-associative, the others are left-associative.
+func operandPrecedences(e *ast.InfixExpression) (left, right int) {
+	prec := precedence(e)
+	if e.Operator == "^" {
+		return prec + 1, prec
+	}
+	return prec, prec + 1
+}
+
+// fail records the first error, the rest of the tree is still printed but the output is discarded.
+func (p *printer) fail(pos token.Position, what string) {
+	if p.err == nil {
+		p.err = fmt.Errorf("%s: cannot format %s", pos, what)
+	}
+}
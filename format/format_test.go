@@ -0,0 +1,121 @@
+package format
+
+import (
+	"Lisa/ast"
+	"Lisa/lexer"
+	"Lisa/parser"
+	"strings"
+	"testing"
+)
+
+func TestSource(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"var   x=5 ;return x;", "var x = 5;\nreturn x;\n"},
+		{"var add=fn(x,y){return x+y;};add(1,2);", "var add = fn(x, y) {\n\treturn x + y;\n};\nadd(1, 2);\n"},
+		{"fn(){};", "fn() {};\n"},
+		// Strings are printed as written, the escape sequences are kept.
+		{`"a\tb" + "\u{1F600}";`, "\"a\\tb\" + \"\\u{1F600}\";\n"},
+		{`"😀\u{1f600}\"\\\n";`, "\"😀\\u{1f600}\\\"\\\\\\n\";\n"},
+		// Only the parentheses the precedence requires are kept.
+		{"((1 + 2)) * (3 * 4) - (5 - 6) + -(7) ^ 2;", "(1 + 2) * (3 * 4) - (5 - 6) + -7 ^ 2;\n"},
+		{"(2 ^ 3) ^ 4; 2 ^ (3 ^ 4); (-2) ^ 2; -(2 ^ 2);", "(2 ^ 3) ^ 4;\n2 ^ 3 ^ 4;\n(-2) ^ 2;\n-2 ^ 2;\n"},
+		{"(a < b) == (c > d); (fn(x){x;})(1); (-f)(1); -f(1);", "a < b == c > d;\nfn(x) {\n\tx;\n}(1);\n(-f)(1);\n-f(1);\n"},
+		{"[ 1,2 , [ ] ][ 0 ]; a[1 :]; a[: -1]; a[ : ]; (a)[(1 + 1):(2)]; (-a)[0]; -a[0]; (a[0])(1); [ fn(x){x;} ][0](1);", "[1, 2, []][0];\na[1:];\na[:-1];\na[:];\na[1 + 1:2];\n(-a)[0];\n-a[0];\na[0](1);\n[fn(x) {\n\tx;\n}][0](1);\n"},
+		{`var h={ "a" :1,2:{ }, true:[ 1 ] };h[ "a" ];{};if (z) { z; }; {"b": 2};`, "var h = {\"a\": 1, 2: {}, true: [1]};\nh[\"a\"];\n{};\nif (z) {\n\tz;\n}\n{\"b\": 2};\n"},
+		{"0XFF+0o7_55 + 0b1010 * 1_000_000;", "0XFF + 0o7_55 + 0b1010 * 1_000_000;\n"},
+		{"var pi=3.14;-pi * 1.5e-3 / (2E+10 - 1);", "var pi = 3.14;\n-pi * 1.5e-3 / (2E+10 - 1);\n"},
+		// The ';' after an if statement is dropped, the statement ends at the '}' anyway.
+		{"if (z) { z; }; [1]; if (z) { z; }; (-a)[0]; if (z) { z; }; a[0];", "if (z) {\n\tz;\n}\n[1];\nif (z) {\n\tz;\n}\n(-a)[0];\nif (z) {\n\tz;\n}\na[0];\n"},
+		{
+			"if (x > 1) { x; } else { if (y) { y; }; }; -1; if (z) { z; }; (z); if (z) { z; }; (a + b) * c; if (z) { z; }; -a - b;",
+			"if (x > 1) {\n\tx;\n} else {\n\tif (y) {\n\t\ty;\n\t}\n}\n-1;\nif (z) {\n\tz;\n}\nz;\nif (z) {\n\tz;\n}\n(a + b) * c;\nif (z) {\n\tz;\n}\n-a - b;\n",
+		},
+		// An if expression that starts a longer expression statement keeps its parentheses.
+		{
+			"(if (c) { 1; }) + 2; (if (c) { f; })(1); (if (c) { a; })[0]; 1 + if (c) { 2; }; var x = if (c) { 1; } + 2;",
+			"(if (c) {\n\t1;\n}) + 2;\n(if (c) {\n\tf;\n})(1);\n(if (c) {\n\ta;\n})[0];\n1 + if (c) {\n\t2;\n};\nvar x = if (c) {\n\t1;\n} + 2;\n",
+		},
+		// A blank line between statements is kept, but not more than one, and not at the start or the end of a block.
+		{"var a = 1;\n\n\n\nvar b = fn() {\n\n\treturn a;\n\n};\nb();", "var a = 1;\n\nvar b = fn() {\n\treturn a;\n};\nb();\n"},
+		// Comments are kept where they are.
+		{
+			"// Header.\n\n// Doc of x.\nvar x = 1;   // One.\nvar f = fn() { // Opening.\n  // Inside.\n  return x; // Trailing.\n      // Before the closing brace.\n};\n// The end.",
+			"// Header.\n\n// Doc of x.\nvar x = 1; // One.\nvar f = fn() { // Opening.\n\t// Inside.\n\treturn x; // Trailing.\n\t// Before the closing brace.\n};\n// The end.\n",
+		},
+		{"fn() {\n// Only a comment.\n};", "fn() {\n\t// Only a comment.\n};\n"},
+		{
+			"/* Block\n   comment. */\nvar x = /* inline */ 1;\n/* a /* nested */ b */ x;",
+			"/* Block\n   comment. */\nvar x = /* inline */ 1;\n/* a /* nested */ b */\nx;\n",
+		},
+		// A comment inside an expression stays next to the code it describes, the line is broken after a line comment.
+		{"var x = 1 + // One.\n  2;\nx;", "var x = 1 + // One.\n\t2;\nx;\n"},
+		{"var f = fn(/* p */ a) { a; };", "var f = fn(/* p */ a) {\n\ta;\n};\n"},
+		{"if (c) { 1; } /* c */ else { 2; }", "if (c) {\n\t1;\n} /* c */ else {\n\t2;\n}\n"},
+		{"[1, // one\n2, // two\n3];", "[1, // one\n\t2, // two\n\t3];\n"},
+		{"var x = a /* before */ + /* after */ b;\nvar y = a // c\n  - b;", "var x = a /* before */ + /* after */ b;\nvar y = a // c\n\t- b;\n"},
+		{"var h = {\n  \"a\": 1, // a\n  \"b\": 2\n};", "var h = {\"a\": 1, // a\n\t\"b\": 2};\n"},
+	}
+
+	for i, tc := range testCases {
+		got, err := Source("test.lisa", []byte(tc.input))
+		if err != nil {
+			t.Errorf("tests[%d] - error formatting: expected nil, got %v.\n", i, err)
+			continue
+		}
+		if string(got) != tc.expected {
+			t.Errorf("tests[%d] - error formatted source: expected %q, got %q.\n", i, tc.expected, string(got))
+			continue
+		}
+
+		// Formatting the formatted source changes nothing.
+		again, err := Source("test.lisa", got)
+		if err != nil || string(again) != string(got) {
+			t.Errorf("tests[%d] - error formatting twice: expected %q, got %q (%v).\n", i, got, again, err)
+		}
+	}
+}
+
+func TestSource_Errors(t *testing.T) {
+	inputs := []string{
+		"var x = ;",
+		"fn(x { x };",
+		"if (x) { x;",
+	}
+
+	for i, input := range inputs {
+		got, err := Source("test.lisa", []byte(input))
+		if err == nil {
+			t.Errorf("tests[%d] - error formatting %q: expected an error, got %q.\n", i, input, got)
+			continue
+		}
+		if !strings.HasPrefix(err.Error(), "test.lisa:1:") {
+			t.Errorf("tests[%d] - error message: expected the position of the error, got %q.\n", i, err)
+		}
+	}
+}
+
+func TestNode_BadStatement(t *testing.T) {
+	// A tree with syntax errors can't be formatted.
+	p := parser.New(lexer.New("var x = 1; var = 2; x;"))
+	root := p.ParseProgram()
+	if _, isBad := root.Statements[1].(*ast.BadStatement); !isBad {
+		t.Fatalf("error parsing: expected *ast.BadStatement, got %T.\n", root.Statements[1])
+	}
+
+	var buf strings.Builder
+	err := Node(&buf, root)
+	if err == nil {
+		t.Fatalf("error formatting: expected an error, got %q.\n", buf.String())
+	}
+	expected := "1:12: cannot format a statement with syntax errors"
+	if err.Error() != expected {
+		t.Errorf("error message: expected %q, got %q.\n", expected, err.Error())
+	}
+	if buf.Len() != 0 {
+		t.Errorf("error output: expected nothing, got %q.\n", buf.String())
+	}
+}
@@ -0,0 +1,250 @@
+// Package diff produces line-based differences between two texts in the unified diff format.
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// context is the number of unchanged lines shown around the changes of a hunk.
+const context = 3
+
+// op is an edit that turns the old text to the new one, line by line.
+type op struct {
+	// kind is ' ' for a line kept, '-' for a line deleted from the old text and '+' for a line inserted from the new one.
+	kind byte
+	line string
+}
+
+// Diff returns the unified diff that turns old (named oldName) to new (named newName), nil if they're identical.
+// The changes are the fewest lines to delete and insert, found in memory proportional to the line counts (see compare).
+func Diff(oldName string, old []byte, newName string, new []byte) []byte {
+	if bytes.Equal(old, new) {
+		return nil
+	}
+
+	ops := edits(splitLines(old), splitLines(new))
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", oldName, newName)
+
+	// oldLine and newLine count the lines of each text before ops[i].
+	oldLine, newLine := 0, 0
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			oldLine++
+			newLine++
+			i++
+			continue
+		}
+
+		// A hunk starts with the context before the first change, and goes on as long as the changes are close enough to share their context.
+		start := max(i-context, 0)
+		end := i
+		for j := i; j < len(ops); j++ {
+			if ops[j].kind != ' ' {
+				end = j + 1
+			} else if j-end >= 2*context {
+				break
+			}
+		}
+		end = min(end+context, len(ops))
+
+		hunkOld, hunkNew := oldLine-(i-start), newLine-(i-start)
+		oldCount, newCount := 0, 0
+		for _, o := range ops[start:end] {
+			if o.kind != '+' {
+				oldCount++
+			}
+			if o.kind != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(hunkOld, oldCount), hunkRange(hunkNew, newCount))
+		for _, o := range ops[start:end] {
+			out.WriteByte(o.kind)
+			out.WriteString(o.line)
+			if !strings.HasSuffix(o.line, "\n") {
+				out.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		// Continue after the hunk, keeping track of the lines.
+		for _, o := range ops[i:end] {
+			if o.kind != '+' {
+				oldLine++
+			}
+			if o.kind != '-' {
+				newLine++
+			}
+		}
+		i = end
+	}
+
+	return out.Bytes()
+}
+
+// hunkRange formats the range of a hunk in one of the texts, start is the number of lines before it.
+// An empty range refers to the line before it, as the unified format expects.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// splitLines splits text after every newline, the last line doesn't end with one if text doesn't.
+func splitLines(text []byte) []string {
+	if len(text) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(text), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// edits finds the shortest series of ops that turns a to b.
+// The lines both texts start and end with are kept as they are, only the lines in between are compared (see compare).
+func edits(a, b []string) []op {
+	ops := make([]op, 0, max(len(a), len(b)))
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops = keep(ops, a[:prefix])
+	ops = compare(ops, a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])
+	ops = keep(ops, a[len(a)-suffix:])
+
+	// In every run of changes, the deleted lines are shown before the inserted ones.
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		end := i
+		for end < len(ops) && ops[end].kind != ' ' {
+			end++
+		}
+		slices.SortStableFunc(ops[i:end], func(x, y op) int { return int(y.kind) - int(x.kind) })
+		i = end
+	}
+	return ops
+}
+
+// compare appends the shortest series of ops that turns a to b to ops.
+// It's the linear space variant of the algorithm by Eugene W. Myers ("An O(ND) Difference Algorithm and Its Variations", 1986):
+// the texts are split at the middle snake of the shortest edit script (see middleSnake), and both halves are compared recursively.
+// It takes time proportional to the total line count times the number of edits, and memory proportional to the total line count.
+func compare(ops []op, a, b []string) []op {
+	// Stripping the common lines makes sure the middle snake splits the texts into two smaller problems.
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		ops = append(ops, op{' ', a[0]})
+		a, b = a[1:], b[1:]
+	}
+	suffix := 0
+	for suffix < len(a) && suffix < len(b) && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	common := a[len(a)-suffix:]
+	a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+
+	switch {
+	case len(a) == 0:
+		for _, line := range b {
+			ops = append(ops, op{'+', line})
+		}
+	case len(b) == 0:
+		for _, line := range a {
+			ops = append(ops, op{'-', line})
+		}
+	default:
+		x, y, u, v := middleSnake(a, b)
+		ops = compare(ops, a[:x], b[:y])
+		ops = keep(ops, a[x:u])
+		ops = compare(ops, a[u:], b[v:])
+	}
+	return keep(ops, common)
+}
+
+// middleSnake returns the middle snake of the shortest edit script that turns a to b, from (x, y) to (u, v):
+// the diagonal of lines a[x:u] and b[y:v], which are the same, in the middle of the script.
+// The script is searched from both ends at once, until the furthest paths of each direction overlap.
+// a and b must not be empty.
+func middleSnake(a, b []string) (x, y, u, v int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	odd := delta%2 != 0
+	maxD := (n + m + 1) / 2
+
+	// forward[offset+k] is the furthest x reached on the diagonal k = x - y from the start of the texts,
+	// backward[offset+k] is the same from their end, in reversed coordinates (x counts the lines from the end of a).
+	offset := maxD + 1
+	forward := make([]int, 2*offset+1)
+	backward := make([]int, 2*offset+1)
+
+	for d := 0; d <= maxD; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+
+			// The diagonal of the backward paths of d - 1 edits that ends where this one does.
+			if rk := delta - k; odd && -(d-1) <= rk && rk <= d-1 && x+backward[offset+rk] >= n {
+				return startX, startY, x, y
+			}
+		}
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && a[n-1-x] == b[m-1-y] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+
+			// The diagonal of the forward paths of d edits that ends where this one does.
+			if fk := delta - k; !odd && -d <= fk && fk <= d && x+forward[offset+fk] >= n {
+				return n - x, m - y, n - startX, m - startY
+			}
+		}
+	}
+	panic("diff: no middle snake")
+}
+
+// keep appends the lines, unchanged, to ops.
+func keep(ops []op, lines []string) []op {
+	for _, line := range lines {
+		ops = append(ops, op{' ', line})
+	}
+	return ops
+}
@@ -0,0 +1,149 @@
+package diff
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	testCases := []struct {
+		old      string
+		new      string
+		expected string
+	}{
+		{"a\nb\n", "a\nb\n", ""},
+		{
+			"a\nb\nc\n",
+			"a\nB\nc\n",
+			"--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			"",
+			"a\n",
+			"--- old\n+++ new\n@@ -0,0 +1 @@\n+a\n",
+		},
+		{
+			"a\nb",
+			"a\nb\n",
+			"--- old\n+++ new\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+		// Changes far apart are in separate hunks.
+		{
+			"1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			"x\n2\n3\n4\n5\n6\n7\n8\n9\ny\n",
+			"--- old\n+++ new\n@@ -1,4 +1,4 @@\n-1\n+x\n 2\n 3\n 4\n@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+y\n",
+		},
+		// Changes close to each other share their context.
+		{
+			"1\n2\n3\n4\n5\n",
+			"1\n3\n4\n5\nnew\n",
+			"--- old\n+++ new\n@@ -1,5 +1,5 @@\n 1\n-2\n 3\n 4\n 5\n+new\n",
+		},
+	}
+
+	for i, tc := range testCases {
+		got := string(Diff("old", []byte(tc.old), "new", []byte(tc.new)))
+		if got != tc.expected {
+			t.Errorf("tests[%d] - error diff: expected\n%s\ngot\n%s.\n", i, tc.expected, got)
+		}
+	}
+}
+
+func TestDiff_Hunks(t *testing.T) {
+	// Every line of a hunk is accounted for in its header.
+	old := strings.Repeat("same\n", 20) + "old\n" + strings.Repeat("same\n", 20)
+	new := strings.Repeat("same\n", 20) + "new\n" + "added\n" + strings.Repeat("same\n", 20)
+
+	expected := "--- old\n+++ new\n@@ -18,7 +18,8 @@\n same\n same\n same\n-old\n+new\n+added\n same\n same\n same\n"
+	if got := string(Diff("old", []byte(old), "new", []byte(new))); got != expected {
+		t.Errorf("error diff: expected\n%s\ngot\n%s.\n", expected, got)
+	}
+}
+
+func TestEdits(t *testing.T) {
+	// Random texts over a few distinct lines have many ways to match their lines.
+	rng := rand.New(rand.NewSource(1))
+	randomLines := func() []string {
+		lines := make([]string, rng.Intn(12))
+		for i := range lines {
+			lines[i] = string(rune('a' + rng.Intn(3)))
+		}
+		return lines
+	}
+
+	for i := 0; i < 1000; i++ {
+		a, b := randomLines(), randomLines()
+		ops := edits(a, b)
+
+		// The ops turn a to b.
+		var gotA, gotB []string
+		changes := 0
+		for _, o := range ops {
+			if o.kind != '+' {
+				gotA = append(gotA, o.line)
+			}
+			if o.kind != '-' {
+				gotB = append(gotB, o.line)
+			}
+			if o.kind != ' ' {
+				changes++
+			}
+		}
+		if !slices.Equal(gotA, a) || !slices.Equal(gotB, b) {
+			t.Fatalf("tests[%d] - error edits of %q to %q: got %v.\n", i, a, b, ops)
+		}
+
+		// The ops are the fewest, every line not in the longest common subsequence is changed.
+		if expected := len(a) + len(b) - 2*lcsLength(a, b); changes != expected {
+			t.Errorf("tests[%d] - error number of changes from %q to %q: expected %d, got %d.\n", i, a, b, expected, changes)
+		}
+	}
+}
+
+// lcsLength returns the length of the longest common subsequence of a and b.
+func lcsLength(a, b []string) int {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	return lcs[0][0]
+}
+
+func TestDiff_LargeFile(t *testing.T) {
+	// 20,000 lines with a few changes, a table of every pair of lines would take gigabytes.
+	var old, new strings.Builder
+	for i := 0; i < 20000; i++ {
+		fmt.Fprintf(&old, "line %d\n", i)
+		switch {
+		case i%5000 == 0:
+			fmt.Fprintf(&new, "changed %d\n", i)
+		case i%7000 == 0:
+		default:
+			fmt.Fprintf(&new, "line %d\n", i)
+		}
+	}
+
+	got := string(Diff("old", []byte(old.String()), "new", []byte(new.String())))
+	// 4 lines changed and 2 deleted, after the two lines of the header.
+	changes := 0
+	for _, line := range strings.Split(got, "\n")[2:] {
+		if strings.HasPrefix(line, "-") || strings.HasPrefix(line, "+") {
+			changes++
+		}
+	}
+	if changes != 10 {
+		t.Errorf("error number of changed lines: expected %d, got %d in\n%s.\n", 10, changes, got)
+	}
+}
@@ -0,0 +1,334 @@
+// Package lineedit implements a line editor for terminals in raw mode, with history and completion.
+//
+// The supported keys are the usual Emacs-like bindings:
+//
+//	Left, Right, Ctrl-B, Ctrl-F    move the cursor by a character
+//	Home, End, Ctrl-A, Ctrl-E      move the cursor to the start or the end of the line
+//	Up, Down, Ctrl-P, Ctrl-N       go through the history
+//	Ctrl-R                         search the history backwards, again for an older match
+//	Backspace, Delete, Ctrl-D      delete a character, Ctrl-D on an empty line ends the input
+//	Ctrl-K, Ctrl-U, Ctrl-W         delete until the end of the line, until the start of the line, the word before the cursor
+//	Ctrl-L                         clear the screen
+//	Tab                            complete the word before the cursor
+//	Ctrl-C                         discard the line
+package lineedit
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// ErrInterrupted is returned by ReadLine when the user presses Ctrl-C.
+var ErrInterrupted = errors.New("interrupted")
+
+// Editor reads lines from a terminal in raw mode, letting the user edit them.
+// It only reads keys from in and writes the escape sequences that redraw the line to out, putting the terminal in raw mode is up to the caller.
+type Editor struct {
+	in  *bufio.Reader
+	out io.Writer
+
+	// History is the lines the user can go through with Up and Down or search with Ctrl-R.
+	// The lines read by ReadLine aren't added to it, that's up to the caller.
+	History *History
+	// Complete returns the completions of word, which is the identifier before the cursor (possibly empty).
+	// Tab completes nothing if it's nil.
+	Complete func(word string) []string
+}
+
+// New creates an *Editor reading keys from in and writing to out, with an empty history.
+func New(in io.Reader, out io.Writer) *Editor {
+	return &Editor{
+		in:      bufio.NewReader(in),
+		out:     out,
+		History: NewHistory(),
+	}
+}
+
+// line is the state of the line being edited.
+type line struct {
+	prompt string
+	buf    []rune
+	// pos is the index of the cursor in buf.
+	pos int
+	// historyIndex is the line of the history being edited, History.Len() for the new line.
+	historyIndex int
+	// edited is the new line, saved when going through the history.
+	edited []rune
+}
+
+// ReadLine prints prompt and reads a line, which is returned when the user presses Enter.
+// It returns io.EOF if the user presses Ctrl-D on an empty line (or in is exhausted),
+// and ErrInterrupted if the user presses Ctrl-C.
+func (e *Editor) ReadLine(prompt string) (string, error) {
+	l := &line{prompt: prompt, historyIndex: e.History.Len()}
+	e.refresh(l)
+
+	for {
+		k, err := readKey(e.in)
+		if err != nil {
+			if errors.Is(err, io.EOF) && len(l.buf) != 0 {
+				// The input ended in the middle of the line, take it as it is.
+				e.write("\r\n")
+				return string(l.buf), nil
+			}
+			return "", err
+		}
+
+		if k == keyCtrlR {
+			if k, err = e.search(l); err != nil {
+				return "", err
+			}
+			// Replace the search with the line.
+			e.refresh(l)
+		}
+
+		switch k {
+		case keyEnter, keyCtrlJ:
+			e.write("\r\n")
+			return string(l.buf), nil
+		case keyCtrlC:
+			e.write("^C\r\n")
+			return "", ErrInterrupted
+		case keyCtrlD:
+			if len(l.buf) == 0 {
+				e.write("\r\n")
+				return "", io.EOF
+			}
+			l.delete(l.pos, l.pos+1)
+		case keyBackspace, keyCtrlH:
+			l.delete(l.pos-1, l.pos)
+		case keyDelete:
+			l.delete(l.pos, l.pos+1)
+		case keyLeft, keyCtrlB:
+			l.pos = max(l.pos-1, 0)
+		case keyRight, keyCtrlF:
+			l.pos = min(l.pos+1, len(l.buf))
+		case keyHome, keyCtrlA:
+			l.pos = 0
+		case keyEnd, keyCtrlE:
+			l.pos = len(l.buf)
+		case keyCtrlK:
+			l.delete(l.pos, len(l.buf))
+		case keyCtrlU:
+			l.delete(0, l.pos)
+		case keyCtrlW:
+			l.delete(wordStart(l.buf, l.pos), l.pos)
+		case keyUp, keyCtrlP:
+			e.moveInHistory(l, -1)
+		case keyDown, keyCtrlN:
+			e.moveInHistory(l, 1)
+		case keyCtrlL:
+			// Clear the screen and move the cursor to the top.
+			e.write("\x1b[H\x1b[2J")
+		case keyTab:
+			e.complete(l)
+		default:
+			if k >= ' ' && k != keyBackspace {
+				l.insert([]rune{rune(k)})
+			}
+		}
+		e.refresh(l)
+	}
+}
+
+// refresh redraws the line and puts the cursor at its position.
+// The width of every character is assumed to be one column.
+func (e *Editor) refresh(l *line) {
+	var b bytes.Buffer
+	// Go to the start of the line, print it, and clear what's left of the previous one.
+	b.WriteString("\r")
+	b.WriteString(l.prompt)
+	b.WriteString(string(l.buf))
+	b.WriteString("\x1b[K")
+	// Go back to the start and move right to the cursor.
+	b.WriteString("\r")
+	if col := len([]rune(l.prompt)) + l.pos; col > 0 {
+		fmt.Fprintf(&b, "\x1b[%dC", col)
+	}
+	e.write(b.String())
+}
+
+func (e *Editor) write(s string) {
+	io.WriteString(e.out, s)
+}
+
+// insert inserts rs at the cursor, which is moved after them.
+func (l *line) insert(rs []rune) {
+	l.buf = append(l.buf[:l.pos], append(rs, l.buf[l.pos:]...)...)
+	l.pos += len(rs)
+}
+
+// delete deletes buf[from:to], the indexes are clamped to the line.
+// The cursor is moved to from if it's after it.
+func (l *line) delete(from, to int) {
+	from, to = max(from, 0), min(to, len(l.buf))
+	if from >= to {
+		return
+	}
+	l.buf = append(l.buf[:from], l.buf[to:]...)
+	if l.pos > from {
+		l.pos = max(from, l.pos-(to-from))
+	}
+}
+
+// set replaces the line with s, with the cursor at the end.
+func (l *line) set(s []rune) {
+	l.buf = append([]rune(nil), s...)
+	l.pos = len(l.buf)
+}
+
+// moveInHistory replaces the line with the one delta lines away in the history, delta is -1 for an older line and 1 for a newer line.
+// The new line being edited is kept, so it's restored when coming back to it.
+func (e *Editor) moveInHistory(l *line, delta int) {
+	i := l.historyIndex + delta
+	if i < 0 || i > e.History.Len() {
+		return
+	}
+	if l.historyIndex == e.History.Len() {
+		l.edited = append([]rune(nil), l.buf...)
+	}
+
+	l.historyIndex = i
+	if i == e.History.Len() {
+		l.set(l.edited)
+	} else {
+		l.set([]rune(e.History.At(i)))
+	}
+}
+
+// search lets the user search the history backwards for a line containing what's typed, until a key that ends the search is pressed.
+// Ctrl-R goes to the next older match, Backspace deletes the last character of the query, Ctrl-G and Esc cancel the search.
+// Any other key puts the match in the line, and it's returned to be handled as usual, e.g. Enter submits the match.
+func (e *Editor) search(l *line) (key, error) {
+	original := append([]rune(nil), l.buf...)
+	var query []rune
+	// match is the index of the current match in the history, -1 if there's none.
+	match := e.searchHistory(query, e.History.Len()-1)
+
+	for {
+		status := "reverse-i-search"
+		text := ""
+		if match >= 0 {
+			text = e.History.At(match)
+		} else if len(query) > 0 {
+			status = "failing reverse-i-search"
+		}
+		e.write(fmt.Sprintf("\r(%s)`%s': %s\x1b[K", status, string(query), text))
+
+		k, err := readKey(e.in)
+		if err != nil {
+			return 0, err
+		}
+		switch {
+		case k == keyCtrlR:
+			if match > 0 {
+				if older := e.searchHistory(query, match-1); older >= 0 {
+					match = older
+				}
+			}
+		case k == keyBackspace || k == keyCtrlH:
+			if len(query) > 0 {
+				query = query[:len(query)-1]
+				match = e.searchHistory(query, e.History.Len()-1)
+			}
+		case k == keyCtrlG || k == keyEsc:
+			l.set(original)
+			return keyUnknown, nil
+		case k >= ' ':
+			query = append(query, rune(k))
+			// The match stays the same if it still contains the query.
+			start := e.History.Len() - 1
+			if match >= 0 {
+				start = match
+			}
+			match = e.searchHistory(query, start)
+		default:
+			if match >= 0 {
+				l.set([]rune(e.History.At(match)))
+				l.historyIndex = match
+			}
+			return k, nil
+		}
+	}
+}
+
+// searchHistory returns the index of the newest line containing query, starting from the line at start, or -1 if there's none.
+func (e *Editor) searchHistory(query []rune, start int) int {
+	for i := start; i >= 0; i-- {
+		if strings.Contains(e.History.At(i), string(query)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// complete completes the identifier before the cursor.
+// A single completion replaces it, with several ones, their common prefix does,
+// or they're listed if that doesn't complete anything more.
+func (e *Editor) complete(l *line) {
+	if e.Complete == nil {
+		return
+	}
+	start := l.pos
+	for start > 0 && isIdentifierPart(l.buf[start-1]) {
+		start--
+	}
+	word := string(l.buf[start:l.pos])
+
+	completions := e.Complete(word)
+	switch {
+	case len(completions) == 0:
+		// Ring the bell.
+		e.write("\a")
+	case len(completions) == 1:
+		l.insert([]rune(strings.TrimPrefix(completions[0], word)))
+	default:
+		prefix := commonPrefix(completions)
+		if len(prefix) > len(word) {
+			l.insert([]rune(strings.TrimPrefix(prefix, word)))
+			return
+		}
+		// List them below the line, the line is redrawn after them.
+		e.write("\r\n" + strings.Join(completions, "  ") + "\r\n")
+	}
+}
+
+// wordStart returns the index where the word before pos starts, the words are separated by white space.
+// The white space right before pos is part of the word.
+func wordStart(buf []rune, pos int) int {
+	i := pos
+	for i > 0 && unicode.IsSpace(buf[i-1]) {
+		i--
+	}
+	for i > 0 && !unicode.IsSpace(buf[i-1]) {
+		i--
+	}
+	return i
+}
+
+// isIdentifierPart reports whether r can be part of an identifier, like the lexer does.
+func isIdentifierPart(r rune) bool {
+	return r == '_' || unicode.In(r, unicode.L, unicode.Nl, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc)
+}
+
+// commonPrefix returns the longest prefix all the strings share.
+// The strings are compared rune by rune, thus the prefix never ends in the middle of a multi-byte character.
+func commonPrefix(strs []string) string {
+	prefix := []rune(strs[0])
+	for _, s := range strs[1:] {
+		n := 0
+		for _, r := range s {
+			if n == len(prefix) || prefix[n] != r {
+				break
+			}
+			n++
+		}
+		prefix = prefix[:n]
+	}
+	return string(prefix)
+}
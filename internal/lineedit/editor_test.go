@@ -0,0 +1,344 @@
+package lineedit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	up    = "\x1b[A"
+	down  = "\x1b[B"
+	right = "\x1b[C"
+	left  = "\x1b[D"
+	home  = "\x1b[H"
+	end   = "\x1bOF"
+	del   = "\x1b[3~"
+)
+
+func TestEditor_ReadLine(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"var x = 1;\r", "var x = 1;"},
+		{"abc\n", "abc"},
+		{"héllo\r", "héllo"},
+		// Moving the cursor and editing.
+		{"ac" + left + "b\r", "abc"},
+		{"bc" + home + "a" + end + "d\r", "abcd"},
+		{"bc\x01a\x05d\r", "abcd"},
+		{"abcd\x7f\x7f\r", "ab"},
+		{"abcd" + left + left + del + "\r", "abd"},
+		{"abcd\x02\x02\x04\r", "abd"},
+		{"ab" + left + left + left + "\x7f" + right + right + right + "c\r", "abc"},
+		{"var x = 1;" + left + left + "\x0b\r", "var x = "},
+		{"var x = 1;" + left + "\x15\r", ";"},
+		{"var foo  bar\x17\r", "var foo  "},
+		{"var foo  \x17\r", "var "},
+		// Unsupported escape sequences and control characters are ignored.
+		{"a\x1b[15~\x1b[1;5Cb\x00\r", "ab"},
+	}
+
+	for i, tc := range testCases {
+		e := New(strings.NewReader(tc.input), io.Discard)
+		got, err := e.ReadLine("> ")
+		if err != nil {
+			t.Errorf("tests[%d] - error reading line: expected nil, got %v.\n", i, err)
+		}
+		if got != tc.expected {
+			t.Errorf("tests[%d] - error line: expected %q, got %q.\n", i, tc.expected, got)
+		}
+	}
+}
+
+func TestEditor_ReadLine_EndOfInput(t *testing.T) {
+	testCases := []struct {
+		input       string
+		expected    string
+		expectedErr error
+	}{
+		{"", "", io.EOF},
+		{"\x04", "", io.EOF},
+		{"abc\x03", "", ErrInterrupted},
+		// The input ending in the middle of the line.
+		{"abc", "abc", nil},
+	}
+
+	for i, tc := range testCases {
+		e := New(strings.NewReader(tc.input), io.Discard)
+		got, err := e.ReadLine("> ")
+		if !errors.Is(err, tc.expectedErr) {
+			t.Errorf("tests[%d] - error reading line: expected %v, got %v.\n", i, tc.expectedErr, err)
+		}
+		if got != tc.expected {
+			t.Errorf("tests[%d] - error line: expected %q, got %q.\n", i, tc.expected, got)
+		}
+	}
+}
+
+func TestEditor_Refresh(t *testing.T) {
+	var out bytes.Buffer
+	e := New(strings.NewReader("ab"+left+"\r"), &out)
+	if _, err := e.ReadLine("> "); err != nil {
+		t.Fatal(err)
+	}
+
+	// The line is redrawn after every key, with the cursor moved to its position.
+	expected := "\r> \x1b[K\r\x1b[2C" +
+		"\r> a\x1b[K\r\x1b[3C" +
+		"\r> ab\x1b[K\r\x1b[4C" +
+		"\r> ab\x1b[K\r\x1b[3C" +
+		"\r\n"
+	if out.String() != expected {
+		t.Errorf("error output: expected %q, got %q.\n", expected, out.String())
+	}
+}
+
+func newEditorWithHistory(input string, lines ...string) *Editor {
+	e := New(strings.NewReader(input), io.Discard)
+	for _, line := range lines {
+		e.History.Add(line)
+	}
+	return e
+}
+
+func TestEditor_History(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{up + "\r", "third"},
+		{up + up + up + up + "\r", "first"},
+		{up + up + down + "\r", "third"},
+		// The new line is restored when coming back to it.
+		{"new" + up + up + down + down + "\r", "new"},
+		{"new" + up + "!" + down + down + "\r", "new"},
+		{"\x10\x10x\r", "secondx"},
+		{down + "\r", ""},
+	}
+
+	for i, tc := range testCases {
+		e := newEditorWithHistory(tc.input, "first", "second", "third")
+		got, err := e.ReadLine("> ")
+		if err != nil {
+			t.Errorf("tests[%d] - error reading line: expected nil, got %v.\n", i, err)
+		}
+		if got != tc.expected {
+			t.Errorf("tests[%d] - error line: expected %q, got %q.\n", i, tc.expected, got)
+		}
+	}
+}
+
+func TestEditor_Search(t *testing.T) {
+	history := []string{"var add = fn(a, b) { a + b; };", "add(1, 2);", "var x = 5;", "x * 2;"}
+
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		// Enter submits the match.
+		{"\x12add\r", "add(1, 2);"},
+		{"\x12var\r", "var x = 5;"},
+		// Ctrl-R again goes to an older match.
+		{"\x12var\x12\r", "var add = fn(a, b) { a + b; };"},
+		{"\x12var\x12\x12\x12\r", "var add = fn(a, b) { a + b; };"},
+		// Backspace searches the shorter query again.
+		{"\x12x =\x7f\x7f\x7f\r", "x * 2;"},
+		// Another key puts the match in the line, and it's handled as usual.
+		{"\x12add(" + end + " // call\r", "add(1, 2); // call"},
+		// Ctrl-G and Esc cancel the search.
+		{"typed\x12add\x07\r", "typed"},
+		{"typed\x12add\x1b", "typed"},
+		// Without a match, the line is left as it is.
+		{"typed\x12nothing" + end + "\r", "typed"},
+	}
+
+	for i, tc := range testCases {
+		e := newEditorWithHistory(tc.input, history...)
+		got, err := e.ReadLine("> ")
+		if err != nil {
+			t.Errorf("tests[%d] - error reading line: expected nil, got %v.\n", i, err)
+		}
+		if got != tc.expected {
+			t.Errorf("tests[%d] - error line: expected %q, got %q.\n", i, tc.expected, got)
+		}
+	}
+}
+
+func TestEditor_Complete(t *testing.T) {
+	words := []string{"false", "fn", "foo", "foobar", "return", "var", "ünicode"}
+	complete := func(word string) []string {
+		var completions []string
+		for _, w := range words {
+			if strings.HasPrefix(w, word) {
+				completions = append(completions, w)
+			}
+		}
+		return completions
+	}
+
+	testCases := []struct {
+		input          string
+		expected       string
+		expectedOutput string
+	}{
+		{"re\t\r", "return", ""},
+		{"var x = fal\t;\r", "var x = false;", ""},
+		{"ü\t\r", "ünicode", ""},
+		// The common prefix of the completions.
+		{"foo\t\r", "foo", "foo  foobar"},
+		{"fo\t\r", "foo", ""},
+		{"fo\t\t\r", "foo", "foo  foobar"},
+		// The word before the cursor is completed.
+		{"(va)" + left + "\t\r", "(var)", ""},
+		{"x + \t\r", "x + ", "false  fn  foo  foobar  return  var  ünicode"},
+		{"zz\t\r", "zz", "\a"},
+	}
+
+	for i, tc := range testCases {
+		var out bytes.Buffer
+		e := New(strings.NewReader(tc.input), &out)
+		e.Complete = complete
+		got, err := e.ReadLine("> ")
+		if err != nil {
+			t.Errorf("tests[%d] - error reading line: expected nil, got %v.\n", i, err)
+		}
+		if got != tc.expected {
+			t.Errorf("tests[%d] - error line: expected %q, got %q.\n", i, tc.expected, got)
+		}
+		if !strings.Contains(out.String(), tc.expectedOutput) {
+			t.Errorf("tests[%d] - error output: expected it to contain %q, got %q.\n", i, tc.expectedOutput, out.String())
+		}
+	}
+}
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+
+	h, err := LoadHistory(path)
+	if err != nil {
+		t.Fatalf("error loading a history that doesn't exist: expected nil, got %v.\n", err)
+	}
+	for _, line := range []string{"a", "", "b", "b", "a"} {
+		if err := h.Add(line); err != nil {
+			t.Fatalf("error adding %q: expected nil, got %v.\n", line, err)
+		}
+	}
+
+	// Empty lines and the same line twice in a row aren't added.
+	expected := []string{"a", "b", "a"}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "a\nb\na\n" {
+		t.Errorf("error history file: expected %q, got %q.\n", "a\nb\na\n", content)
+	}
+
+	loaded, err := LoadHistory(path)
+	if err != nil {
+		t.Fatalf("error loading history: expected nil, got %v.\n", err)
+	}
+	if loaded.Len() != len(expected) {
+		t.Fatalf("error length of history: expected %d, got %d.\n", len(expected), loaded.Len())
+	}
+	for i, line := range expected {
+		if loaded.At(i) != line {
+			t.Errorf("tests[%d] - error history line: expected %q, got %q.\n", i, line, loaded.At(i))
+		}
+	}
+}
+
+func Test_commonPrefix(t *testing.T) {
+	testCases := []struct {
+		strs     []string
+		expected string
+	}{
+		{[]string{"foo", "foobar"}, "foo"},
+		{[]string{"foobar", "foo"}, "foo"},
+		{[]string{"abc", "xyz"}, ""},
+		{[]string{"same"}, "same"},
+		// 'é' and 'è' share their first byte, which isn't a prefix on its own.
+		{[]string{"café", "cafè"}, "caf"},
+		{[]string{"日本語", "日本"}, "日本"},
+	}
+
+	for i, tc := range testCases {
+		if got := commonPrefix(tc.strs); got != tc.expected {
+			t.Errorf("tests[%d] - error common prefix of %q: expected %q, got %q.\n", i, tc.strs, tc.expected, got)
+		}
+	}
+}
+
+func TestHistory_Max(t *testing.T) {
+	h := NewHistory()
+	for i := 0; i < maxHistory+10; i++ {
+		h.Add(strings.Repeat("x", i+1))
+	}
+
+	if h.Len() != maxHistory {
+		t.Fatalf("error length of history: expected %d, got %d.\n", maxHistory, h.Len())
+	}
+	// The oldest lines are dropped.
+	if len(h.At(0)) != 11 {
+		t.Errorf("error oldest line: expected length %d, got %d.\n", 11, len(h.At(0)))
+	}
+}
+
+func TestHistory_TrimFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	countLines := func() int {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.Count(string(content), "\n")
+	}
+
+	// A file with more than twice the lines kept is trimmed when it's loaded.
+	var content strings.Builder
+	for i := 0; i < 2*maxHistory+1; i++ {
+		fmt.Fprintf(&content, "line %d\n", i)
+	}
+	if err := os.WriteFile(path, []byte(content.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	h, err := LoadHistory(path)
+	if err != nil {
+		t.Fatalf("error loading history: expected nil, got %v.\n", err)
+	}
+	if n := countLines(); n != maxHistory {
+		t.Errorf("error lines in the file after loading: expected %d, got %d.\n", maxHistory, n)
+	}
+
+	// Lines are appended until the file holds twice the lines kept, then it's trimmed again.
+	for i := 0; i < maxHistory; i++ {
+		if err := h.Add(fmt.Sprintf("new %d", i)); err != nil {
+			t.Fatalf("error adding a line: expected nil, got %v.\n", err)
+		}
+	}
+	if n := countLines(); n != 2*maxHistory {
+		t.Errorf("error lines in the file before trimming: expected %d, got %d.\n", 2*maxHistory, n)
+	}
+	if err := h.Add("last"); err != nil {
+		t.Fatalf("error adding a line: expected nil, got %v.\n", err)
+	}
+	if n := countLines(); n != maxHistory {
+		t.Errorf("error lines in the file after trimming: expected %d, got %d.\n", maxHistory, n)
+	}
+
+	loaded, err := LoadHistory(path)
+	if err != nil {
+		t.Fatalf("error loading history: expected nil, got %v.\n", err)
+	}
+	if loaded.Len() != maxHistory || loaded.At(loaded.Len()-1) != "last" || loaded.At(0) != "new 1" {
+		t.Errorf("error trimmed history: expected %d lines from %q to %q, got %d lines from %q to %q.\n",
+			maxHistory, "new 1", "last", loaded.Len(), loaded.At(0), loaded.At(loaded.Len()-1))
+	}
+}
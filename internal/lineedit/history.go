@@ -0,0 +1,116 @@
+package lineedit
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// maxHistory is the number of lines a History keeps, the oldest ones are dropped.
+// The file of a persisted History is rewritten with the lines it keeps once it holds twice as many.
+const maxHistory = 1000
+
+// History is the lines entered so far, from the oldest to the newest.
+// It's persisted in a file, one line per line, so it's kept across sessions.
+type History struct {
+	entries []string
+	// path is the file the history is persisted in, empty if it isn't persisted.
+	path string
+	// fileLines is the number of lines in the file, including the ones dropped from entries.
+	fileLines int
+}
+
+// NewHistory creates a History that isn't persisted.
+func NewHistory() *History {
+	return &History{}
+}
+
+// LoadHistory creates a History persisted in the file at path, with the lines already in it.
+// A file holding more than twice the lines a History keeps is trimmed to the ones it keeps.
+// A file that doesn't exist yet is created when the first line is added.
+func LoadHistory(path string) (*History, error) {
+	h := &History{path: path}
+
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		h.add(scanner.Text())
+		h.fileLines++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if h.fileLines > 2*maxHistory {
+		if err := h.rewrite(); err != nil {
+			return nil, err
+		}
+	}
+	return h, nil
+}
+
+// Add appends line to the history, and to its file if it's persisted.
+// An empty line, or the same line as the newest one, isn't added.
+func (h *History) Add(line string) error {
+	if !h.add(line) || h.path == "" {
+		return nil
+	}
+	if h.fileLines >= 2*maxHistory {
+		return h.rewrite()
+	}
+
+	h.fileLines++
+	f, err := os.OpenFile(h.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(line + "\n"); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// rewrite replaces the content of the file with the entries, dropping the lines that aren't kept anymore.
+func (h *History) rewrite() error {
+	var content strings.Builder
+	for _, line := range h.entries {
+		content.WriteString(line + "\n")
+	}
+	if err := os.WriteFile(h.path, []byte(content.String()), 0o600); err != nil {
+		return err
+	}
+	h.fileLines = len(h.entries)
+	return nil
+}
+
+// add appends line to the entries and reports whether it's added.
+func (h *History) add(line string) bool {
+	if line == "" || (len(h.entries) > 0 && h.entries[len(h.entries)-1] == line) {
+		return false
+	}
+	h.entries = append(h.entries, line)
+	if len(h.entries) > maxHistory {
+		h.entries = h.entries[len(h.entries)-maxHistory:]
+	}
+	return true
+}
+
+// Len returns the number of lines in the history.
+func (h *History) Len() int {
+	return len(h.entries)
+}
+
+// At returns the i-th line, the oldest one is at 0.
+func (h *History) At(i int) string {
+	return h.entries[i]
+}
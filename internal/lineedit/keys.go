@@ -0,0 +1,131 @@
+package lineedit
+
+import (
+	"bufio"
+	"unicode/utf8"
+)
+
+// key is a key pressed by the user: a printable character, a control character or one of the special keys below.
+type key rune
+
+const (
+	keyCtrlA     key = 1
+	keyCtrlB     key = 2
+	keyCtrlC     key = 3
+	keyCtrlD     key = 4
+	keyCtrlE     key = 5
+	keyCtrlF     key = 6
+	keyCtrlG     key = 7
+	keyCtrlH     key = 8
+	keyTab       key = 9
+	keyCtrlJ     key = 10
+	keyCtrlK     key = 11
+	keyCtrlL     key = 12
+	keyEnter     key = 13
+	keyCtrlN     key = 14
+	keyCtrlP     key = 16
+	keyCtrlR     key = 18
+	keyCtrlU     key = 21
+	keyCtrlW     key = 23
+	keyEsc       key = 27
+	keyBackspace key = 127
+)
+
+// The special keys are sent as escape sequences, they're given negative values so they don't clash with any character.
+const (
+	keyUp key = -(iota + 1)
+	keyDown
+	keyRight
+	keyLeft
+	keyHome
+	keyEnd
+	keyDelete
+	// keyUnknown is an escape sequence that isn't supported, it's ignored.
+	keyUnknown
+)
+
+// readKey reads the next key from in.
+func readKey(in *bufio.Reader) (key, error) {
+	r, _, err := in.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+	if r != utf8.RuneError && key(r) != keyEsc {
+		return key(r), nil
+	}
+	if r == utf8.RuneError {
+		// Not valid UTF-8, drop it.
+		return keyUnknown, nil
+	}
+
+	// An escape sequence is sent at once, a lone Esc isn't followed by anything yet.
+	if in.Buffered() == 0 {
+		return keyEsc, nil
+	}
+	b, err := in.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	switch b {
+	case '[':
+		return readCSI(in)
+	case 'O':
+		// SS3 sequences, sent by some terminals for the arrows, Home and End.
+		b, err := in.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		return finalKey(b), nil
+	default:
+		return keyUnknown, nil
+	}
+}
+
+// readCSI reads a control sequence after 'Esc [', e.g. 'Esc [ A' for the up arrow or 'Esc [ 3 ~' for Delete.
+func readCSI(in *bufio.Reader) (key, error) {
+	var params []byte
+	for {
+		b, err := in.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		// The parameters are followed by a final byte in the range '@' to '~'.
+		if b < 0x40 || b > 0x7e {
+			params = append(params, b)
+			continue
+		}
+		if b != '~' {
+			return finalKey(b), nil
+		}
+		switch string(params) {
+		case "1", "7":
+			return keyHome, nil
+		case "4", "8":
+			return keyEnd, nil
+		case "3":
+			return keyDelete, nil
+		default:
+			return keyUnknown, nil
+		}
+	}
+}
+
+// finalKey returns the key of the final byte of an escape sequence.
+func finalKey(b byte) key {
+	switch b {
+	case 'A':
+		return keyUp
+	case 'B':
+		return keyDown
+	case 'C':
+		return keyRight
+	case 'D':
+		return keyLeft
+	case 'H':
+		return keyHome
+	case 'F':
+		return keyEnd
+	default:
+		return keyUnknown
+	}
+}
@@ -0,0 +1,25 @@
+// Package term puts terminals in raw mode, which the line editor of the REPL needs to read key by key.
+// It's implemented with the ioctls of the syscall package, on the systems that don't support them, no file is a terminal.
+package term
+
+// State is the state of a terminal, to restore it after it's put in raw mode.
+type State struct {
+	state
+}
+
+// IsTerminal reports whether fd refers to a terminal.
+func IsTerminal(fd int) bool {
+	return isTerminal(fd)
+}
+
+// MakeRaw puts the terminal fd in raw mode: the input is available key by key, without being echoed,
+// and the keys like Ctrl-C are read instead of sending signals.
+// It returns the previous state of the terminal, which should be restored with Restore.
+func MakeRaw(fd int) (*State, error) {
+	return makeRaw(fd)
+}
+
+// Restore puts the terminal fd back in the state returned by MakeRaw.
+func Restore(fd int, s *State) error {
+	return restore(fd, s)
+}
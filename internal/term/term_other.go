@@ -0,0 +1,21 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package term
+
+import "errors"
+
+type state struct{}
+
+var errUnsupported = errors.New("terminals aren't supported on this system")
+
+func isTerminal(fd int) bool {
+	return false
+}
+
+func makeRaw(fd int) (*State, error) {
+	return nil, errUnsupported
+}
+
+func restore(fd int, s *State) error {
+	return errUnsupported
+}
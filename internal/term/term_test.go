@@ -0,0 +1,21 @@
+package term
+
+import (
+	"os"
+	"testing"
+)
+
+func TestIsTerminal(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "term")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if IsTerminal(int(f.Fd())) {
+		t.Errorf("error regular file: expected not a terminal, got a terminal.\n")
+	}
+	if _, err := MakeRaw(int(f.Fd())); err == nil {
+		t.Errorf("error raw mode of a regular file: expected an error, got nil.\n")
+	}
+}
@@ -0,0 +1,64 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package term
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+type state struct {
+	termios syscall.Termios
+}
+
+func isTerminal(fd int) bool {
+	_, err := getTermios(fd)
+	return err == nil
+}
+
+func makeRaw(fd int) (*State, error) {
+	termios, err := getTermios(fd)
+	if err != nil {
+		return nil, err
+	}
+	old := &State{state{termios: *termios}}
+
+	// The same flags as cfmakeraw(3).
+	termios.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP | syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	termios.Oflag &^= syscall.OPOST
+	termios.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	termios.Cflag &^= syscall.CSIZE | syscall.PARENB
+	termios.Cflag |= syscall.CS8
+	// A read returns as soon as a byte is available.
+	termios.Cc[syscall.VMIN] = 1
+	termios.Cc[syscall.VTIME] = 0
+
+	if err := setTermios(fd, termios); err != nil {
+		return nil, err
+	}
+	return old, nil
+}
+
+func restore(fd int, s *State) error {
+	return setTermios(fd, &s.termios)
+}
+
+func getTermios(fd int) (*syscall.Termios, error) {
+	termios := &syscall.Termios{}
+	if err := ioctl(fd, ioctlGetTermios, uintptr(unsafe.Pointer(termios))); err != nil {
+		return nil, err
+	}
+	return termios, nil
+}
+
+func setTermios(fd int, termios *syscall.Termios) error {
+	return ioctl(fd, ioctlSetTermios, uintptr(unsafe.Pointer(termios)))
+}
+
+func ioctl(fd int, req uint, arg uintptr) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(req), arg)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
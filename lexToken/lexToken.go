@@ -1,5 +1,10 @@
 package lexToken
 
+import (
+	"fmt"
+	"sort"
+)
+
 const (
 	// ILLEGAL signifies a token/character we don't know illegal.
 	ILLEGAL = "ILLEGAL"
@@ -22,6 +27,13 @@ const (
 
 	// INT is the integer type.
 	INT = "INT"
+	// FLOAT is the type of a floating point number, with a fraction and/or an exponent, e.g. '3.14' or '1.5e-3'.
+	FLOAT = "FLOAT"
+	// STRING is the type of a double-quoted string literal, its literal is the value after resolving escape sequences.
+	STRING = "STRING"
+	// COMMENT is the type of a line comment ('// ...') or a block comment ('/* ... */'), its literal is the comment including its markers.
+	// Comments are only returned as tokens if the lexer is asked to, otherwise they're attached to the next token as trivia.
+	COMMENT = "COMMENT"
 
 	ASSIGN      = "="
 	PLUS        = "+"
@@ -33,11 +45,15 @@ const (
 
 	COMMA     = ","
 	SEMICOLON = ";"
+	// COLON separates the bounds of a slice, e.g. 'arr[1:3]', and a key from its value in a hash literal, e.g. '{"a": 1}'.
+	COLON = ":"
 
 	LPAREN      = "("
 	RPAREN      = ")"
 	LBRACE      = "{"
 	RBRACE      = "}"
+	LBRACKET    = "["
+	RBRACKET    = "]"
 	LESSTHAN    = "<"
 	GREATERTHAN = ">"
 )
@@ -61,6 +77,62 @@ type Token struct {
 	Type LexicalType
 	// Literal is the parsed value of a token.
 	Literal string
+	// Raw is the source text of a STRING token, with the quotes and the escape sequences as written, e.g. '"a\tb"'.
+	// It's empty for the other types, whose literal is already their source text, and for tokens not read from source.
+	Raw string
+	// Span is where the token is located in the source code.
+	Span Span
+	// LeadingComments are the comments between the previous token and this one, in the order they appear.
+	// They don't affect the meaning of the program, but tools like the formatter need them to reproduce the source.
+	LeadingComments []Comment
+}
+
+// Pos returns the position of the first character of the token.
+func (t *Token) Pos() Position {
+	return t.Span.Start
+}
+
+// Position is a location in the source code.
+type Position struct {
+	// Filename is the name of the source file, empty if the source isn't read from a file (e.g. the REPL).
+	Filename string
+	// Offset is the byte offset, starting at 0.
+	Offset int
+	// Line is the line number, starting at 1.
+	Line int
+	// Column is the byte offset within the line, starting at 1.
+	Column int
+}
+
+// IsValid reports whether the position is set by a lexer.
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// String returns the position in the form of 'file:line:column', 'line:column' if there's no filename, or '-' if the position isn't valid.
+func (p Position) String() string {
+	if !p.IsValid() {
+		return "-"
+	}
+	if p.Filename == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.Filename, p.Line, p.Column)
+}
+
+// Span is the range of a token in the source code.
+// Start is the position of the first character of the token, End is the position right after the last character.
+type Span struct {
+	Start Position
+	End   Position
+}
+
+// Comment is a comment in the source code, it's kept as trivia attached to the token that follows it.
+type Comment struct {
+	// Text is the comment including its markers, e.g. '// note'.
+	Text string
+	// Span is where the comment is located in the source code.
+	Span Span
 }
 
 // New creates a new *token.
@@ -81,3 +153,13 @@ func LookUpReservedWord(ident string) (lt LexicalType, isReserved bool) {
 	}
 	return lt, isReserved
 }
+
+// ReservedWords returns the reserved words in reservedWordsTable, sorted.
+func ReservedWords() []string {
+	words := make([]string, 0, len(reservedWordsTable))
+	for word := range reservedWordsTable {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	return words
+}
@@ -19,3 +19,36 @@ func Test_LookUpReservedWord(t *testing.T) {
 		}
 	}
 }
+
+func TestReservedWords(t *testing.T) {
+	words := ReservedWords()
+	if len(words) != len(reservedWordsTable) {
+		t.Fatalf("error length of reserved words: expected %d, got %d.\n", len(reservedWordsTable), len(words))
+	}
+	for i, word := range words {
+		if _, isReserved := LookUpReservedWord(word); !isReserved {
+			t.Errorf("tests[%d] - error reserved word %q: expected reserved, got not reserved.\n", i, word)
+		}
+		if i > 0 && words[i-1] >= word {
+			t.Errorf("tests[%d] - error order: expected %q before %q.\n", i, words[i-1], word)
+		}
+	}
+}
+
+func TestPosition_String(t *testing.T) {
+	testCases := []struct {
+		pos      Position
+		expected string
+	}{
+		{Position{}, "-"},
+		{Position{Offset: 4, Line: 1, Column: 5}, "1:5"},
+		{Position{Filename: "main.lisa", Offset: 20, Line: 3, Column: 2}, "main.lisa:3:2"},
+	}
+
+	for i, tc := range testCases {
+		re := tc.pos.String()
+		if re != tc.expected {
+			t.Errorf("tests[%d] - error position string: expected %s, got %s.\n", i, tc.expected, re)
+		}
+	}
+}
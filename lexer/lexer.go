@@ -2,31 +2,93 @@ package lexer
 
 import (
 	token "Lisa/lexToken"
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// readChunkSize is the number of bytes a streaming lexer reads from its reader at a time.
+const readChunkSize = 4096
+
+// The literals of the token.ILLEGAL for input that ends before a string or a block comment is closed.
+// They tell incomplete input apart from malformed input, e.g. the REPL reads more lines when it encounters them.
+const (
+	UnterminatedString       = "unterminated string literal"
+	UnterminatedBlockComment = "unterminated block comment"
 )
 
 // Lexer is an instance that is responsible for taking source code as input and output the tokens that represent it.
-// It goes through it's preloaded input and output the token it recognize, token by token.
-// TODO: To make debugging easier, initialize the Lexer with an io.Reader and the filename of the file the lexer is going through, which helps provide the line number, column number and the filename to a token.
+// It goes through it's input and output the token it recognize, token by token.
+// The input is either preloaded (New, NewFile) or read incrementally from an io.Reader (NewFromReader).
+// Every token carries the filename, line and column it's located at, which makes debugging easier.
 type Lexer struct {
+	// filename is the name of the file the input is read from, empty if the input isn't read from a file.
+	filename string
 	// input is content that is parsed into the Lexer.
-	input string
+	// When reading from a reader, it's a window of the content starting at offset base, which only holds the token being read and what's loaded after it.
+	// The window grows by appending to it and is trimmed by reslicing, thus the bytes it holds are only copied when it outgrows its array.
+	input []byte
+	// base is the offset of input[0] in the whole content, it's always 0 for a preloaded input.
+	base int
+	// reader is where the rest of the content is read from, nil for a preloaded input.
+	reader *bufio.Reader
+	// chunk is the buffer for reading from reader.
+	chunk []byte
+	// readErr is the error that stops reading from reader, io.EOF when the content is exhausted.
+	readErr error
 	// position is the current position in input (which points to current char ch).
 	position int
 	// readPosition is the current reading position in input (the next character, one after current char ch).
 	readPosition int
-	// ch is the current character under examination.
-	// TODO: While using type byte supports ASCII (which can br compared as integers), UTF-8 is a must (change byte to rune and read it bytes wide).
-	ch byte
+	// ch is the current character under examination, decoded from the UTF-8 encoded input.
+	// It's utf8.RuneError if the input isn't valid UTF-8 at position.
+	ch rune
+	// chWidth is the number of bytes ch takes in the input, 0 before the first char is read.
+	chWidth int
+	// line is the line number of the current char ch, starting at 1.
+	line int
+	// column is the byte offset of the current char ch within its line, starting at 1.
+	// It's advanced by chWidth when reading the next char.
+	column int
+	// emitComments makes ReadNextToken return the comments as token.COMMENT, see EmitComments.
+	emitComments bool
+}
+
+// Option configures a Lexer when it's created.
+type Option func(*Lexer)
+
+// EmitComments makes the lexer return every comment as a token.COMMENT, whose literal is the comment including its markers.
+// By default, comments are skipped and attached to the token after them as trivia (see token.Token.LeadingComments).
+func EmitComments() Option {
+	return func(l *Lexer) {
+		l.emitComments = true
+	}
 }
 
 // New creates a new pointer of Lexer.
-func New(data string) *Lexer {
+func New(data string, opts ...Option) *Lexer {
+	return NewFile("", data, opts...)
+}
+
+// NewFile creates a new pointer of Lexer for the content of a file.
+// filename is attached to the position of every token the lexer produces.
+func NewFile(filename string, data string, opts ...Option) *Lexer {
 	newLexer := &Lexer{
-		input:        data,
+		filename:     filename,
+		input:        []byte(data),
 		position:     0,
 		readPosition: 0,
 		ch:           0,
+		line:         1,
+		column:       1,
+	}
+	for _, opt := range opts {
+		opt(newLexer)
 	}
 
 	// Initialize the lexer position.
@@ -35,16 +97,57 @@ func New(data string) *Lexer {
 	return newLexer
 }
 
+// NewFromReader creates a new pointer of Lexer that reads its input incrementally from r.
+// Only the token being read is kept in memory, which makes it suitable for large files or interactive input.
+// It produces exactly the same tokens as New with the whole content of r.
+// name is attached to the position of every token the lexer produces.
+func NewFromReader(name string, r io.Reader, opts ...Option) *Lexer {
+	newLexer := &Lexer{
+		filename:     name,
+		reader:       bufio.NewReader(r),
+		chunk:        make([]byte, readChunkSize),
+		position:     0,
+		readPosition: 0,
+		ch:           0,
+		line:         1,
+		column:       1,
+	}
+	for _, opt := range opts {
+		opt(newLexer)
+	}
+
+	// Initialize the lexer position.
+	newLexer.readChar()
+
+	return newLexer
+}
+
+// Err returns the error that stopped the lexer from reading its reader, nil if the content is read to the end (or the input is preloaded).
+// After such an error, the lexer treats the input as ended.
+func (l *Lexer) Err() error {
+	if errors.Is(l.readErr, io.EOF) {
+		return nil
+	}
+	return l.readErr
+}
+
 // ReadNextToken parses the input and transform it into a defined Token type.
 // The function should be called repeatedly.
 func (l *Lexer) ReadNextToken() *token.Token {
 	var tok *token.Token
 	var lt token.LexicalType
 	var literal string
-	var isReserved bool
+	// readAhead is set when the current char is already the one after the token, e.g. for an identifier, a reserved word or a number.
+	var readAhead bool
 
-	// Skip white space before analyzing.
-	l.skipWhiteSpace()
+	// Skip white space and comments before analyzing, the comments are attached to the token.
+	comments, commentTok := l.skipTrivia()
+	if commentTok != nil {
+		commentTok.LeadingComments = comments
+		return commentTok
+	}
+	l.discard()
+	start := l.currentPosition()
 
 	switch l.ch {
 	case '!':
@@ -121,6 +224,20 @@ func (l *Lexer) ReadNextToken() *token.Token {
 		lt = token.COMMA
 		literal = string(l.ch)
 		tok = token.New(lt, literal)
+	case ':':
+		lt = token.COLON
+		literal = string(l.ch)
+		tok = token.New(lt, literal)
+	case '[':
+		lt = token.LBRACKET
+		literal = string(l.ch)
+		tok = token.New(lt, literal)
+	case ']':
+		lt = token.RBRACKET
+		literal = string(l.ch)
+		tok = token.New(lt, literal)
+	case '"':
+		tok = l.readString()
 	case 0:
 		lt = token.EOF
 		literal = ""
@@ -128,97 +245,344 @@ func (l *Lexer) ReadNextToken() *token.Token {
 	default:
 		// Not one of the recognized characters.
 		// This is where the lexical reader encounters a letter.
-		if isLetter(l.ch) {
+		if l.ch == utf8.RuneError && l.chWidth == 1 {
+			// The input isn't valid UTF-8 here, a genuine U+FFFD in the input is 3 bytes wide.
+			tok = token.New(token.ILLEGAL, fmt.Sprintf("invalid UTF-8 encoding: byte 0x%02x", l.input[l.position-l.base]))
+		} else if isLetter(l.ch) {
 
 			// Move the pointer and get the current identifier.
 			identifier := l.readIdentifier()
 
 			// Determine the type of the reading literal.
-			lt, isReserved = token.LookUpReservedWord(identifier)
+			lt, _ = token.LookUpReservedWord(identifier)
 			tok = token.New(lt, identifier)
+			readAhead = true
 		} else if isDigit(l.ch) {
-			number := l.readNumber()
-			tok = token.New(token.INT, number)
+			tok = l.readNumber()
+			readAhead = true
 		} else {
 			tok = token.New(token.ILLEGAL, string(l.ch))
 		}
 	}
 
-	// After checking token, move the lexical pointer to the next position if it's a reserved word, or when type is token.IDENT, token.INT.
-	if isReserved || tok.Type == token.IDENT || tok.Type == token.INT {
-		// Early exit here is necessary since the loop for readIdentifier or readNumber jumps one step forward, thus we don't need another jump below.
-		return tok
-	} else {
+	// After checking token, move the lexical pointer to the next position unless the token is read up to the char after it already.
+	// The loop for readIdentifier or readNumber already jumps one step forward, thus we don't need another jump for those.
+	if !readAhead {
 		l.readChar()
-		return tok
+	}
+
+	tok.Span = token.Span{Start: start, End: l.currentPosition()}
+	tok.LeadingComments = comments
+	return tok
+}
+
+// currentPosition returns the position of the current char ch.
+func (l *Lexer) currentPosition() token.Position {
+	return token.Position{
+		Filename: l.filename,
+		Offset:   l.position,
+		Line:     l.line,
+		Column:   l.column,
 	}
 }
 
 // readChar gives us the next character and advances our position in the input string.
-// The current character is stored in ch.
+// The current character is stored in ch, it's decoded from UTF-8 so it can be more than one byte wide.
 // It checks whether it reached the end of the input.
 // If we reached the end of the input, we set l.ch to 0, otherwise l.ch is set to the next character.
 func (l *Lexer) readChar() {
+	// Moving past a newline starts a new line.
+	if l.ch == '\n' {
+		l.line++
+		l.column = 0
+	}
+	// Columns count bytes, like offsets.
+	l.column += l.chWidth
+
+	// Whether read succeeds or not, move the read position to readPosition.
+	l.position = l.readPosition
+
 	// Checking what's coming up next first.
-	if l.readPosition >= len(l.input) {
-		// EOF.
+	l.fill()
+	if l.readPosition-l.base >= len(l.input) {
+		// EOF. It's treated as a one byte wide character, so the positions keep moving forward.
 		l.ch = 0
+		l.chWidth = 1
 	} else {
 		// Assign the current reading char to ch.
-		l.ch = l.input[l.readPosition]
+		l.ch, l.chWidth = utf8.DecodeRune(l.input[l.readPosition-l.base:])
 	}
 
-	// Whether read succeeds or not, move the read position to readPosition.
-	l.position = l.readPosition
 	// Point the read position to the next character in the input.
-	l.readPosition++
+	l.readPosition += l.chWidth
 }
 
-// readIdentifier reads in an identifier and advances our lexers' positions until it encounters a non-letter character.
+// readIdentifier reads in an identifier and advances our lexers' positions until it encounters a character that can't continue an identifier.
+// An identifier starts with a letter (see isLetter) and continues with letters, digits, combining marks or connector punctuations (see isIdentifierPart).
 func (l *Lexer) readIdentifier() string {
 	position := l.position
+	if !isLetter(l.ch) {
+		return ""
+	}
 
-	// Check whether l.ch is a letter until reaches some character that isn't(possibly a white space or a delimiter).
-	for isLetter(l.ch) {
+	// Check whether l.ch is part of an identifier until reaches some character that isn't(possibly a white space or a delimiter).
+	for isIdentifierPart(l.ch) {
 		// Move l.position by reading through the characters one by one.
 		l.readChar()
 	}
-	currIdent := l.input[position:l.position]
+	currIdent := l.slice(position, l.position)
 	return currIdent
 }
 
+// readString reads in a double-quoted string literal, the current char ch is the opening quote.
+// After reading, ch is the closing quote (or EOF if the string is unterminated).
+// The literal of the returned token.STRING is the value of the string, with the escape sequences \n, \t, \\, \" and \u{...} resolved, its raw text is the string as written.
+// An unterminated string or a malformed escape sequence results in a token.ILLEGAL describing the problem, located at the opening quote.
+func (l *Lexer) readString() *token.Token {
+	position := l.position
+	var value strings.Builder
+	// errMsg is the first problem found, the rest of the string is still read so the lexer continues after the closing quote.
+	var errMsg string
+
+	for {
+		l.readChar()
+
+		switch {
+		case l.ch == '"':
+			if errMsg != "" {
+				return token.New(token.ILLEGAL, errMsg)
+			}
+			tok := token.New(token.STRING, value.String())
+			tok.Raw = l.slice(position, l.position+1)
+			return tok
+		case l.ch == 0:
+			return token.New(token.ILLEGAL, UnterminatedString)
+		case l.ch == utf8.RuneError && l.chWidth == 1:
+			if errMsg == "" {
+				errMsg = fmt.Sprintf("invalid UTF-8 encoding in string literal: byte 0x%02x", l.input[l.position-l.base])
+			}
+		case l.ch == '\\':
+			ch, escErr := l.readEscape()
+			if escErr != "" && errMsg == "" {
+				errMsg = escErr
+			}
+			value.WriteRune(ch)
+		default:
+			value.WriteRune(l.ch)
+		}
+	}
+}
+
+// readEscape reads in an escape sequence in a string literal, the current char ch is the backslash.
+// After reading, ch is the last char of the escape sequence.
+// It returns the escaped character, or a message describing why the escape sequence is malformed.
+func (l *Lexer) readEscape() (rune, string) {
+	// Don't move past EOF, the string is unterminated anyway.
+	if l.peekNextChar() == 0 {
+		return 0, "unterminated escape sequence in string literal"
+	}
+	l.readChar()
+
+	switch l.ch {
+	case 'n':
+		return '\n', ""
+	case 't':
+		return '\t', ""
+	case '\\':
+		return '\\', ""
+	case '"':
+		return '"', ""
+	case 'u':
+		return l.readUnicodeEscape()
+	default:
+		return 0, fmt.Sprintf("unknown escape sequence in string literal: \\%c", l.ch)
+	}
+}
+
+// readUnicodeEscape reads in the '{...}' part of a '\u{...}' escape sequence, the current char ch is the 'u'.
+// The braces enclose 1 to 6 hexadecimal digits of a Unicode code point, e.g. '\u{1F600}'.
+// After reading, ch is the closing brace (or the char that's found in place of it).
+func (l *Lexer) readUnicodeEscape() (rune, string) {
+	if l.peekNextChar() != '{' {
+		return 0, "invalid unicode escape sequence in string literal: expected '{' after \\u"
+	}
+	l.readChar()
+
+	var digits strings.Builder
+	for next := l.peekNextChar(); next != '}'; next = l.peekNextChar() {
+		if next == '"' || next == 0 {
+			return 0, "invalid unicode escape sequence in string literal: expected '}'"
+		}
+		l.readChar()
+		digits.WriteRune(l.ch)
+	}
+	// Move to the closing brace.
+	l.readChar()
+
+	if digits.Len() == 0 || digits.Len() > 6 {
+		return 0, fmt.Sprintf("invalid unicode escape sequence in string literal: \\u{%s} should have 1 to 6 hexadecimal digits", digits.String())
+	}
+	codePoint, err := strconv.ParseUint(digits.String(), 16, 32)
+	if err != nil {
+		return 0, fmt.Sprintf("invalid unicode escape sequence in string literal: \\u{%s} isn't hexadecimal", digits.String())
+	}
+	if !utf8.ValidRune(rune(codePoint)) {
+		return 0, fmt.Sprintf("invalid unicode escape sequence in string literal: \\u{%s} isn't a valid code point", digits.String())
+	}
+	return rune(codePoint), ""
+}
+
+// integerPrefixes maps the letter after the '0' of a prefixed integer literal (e.g. 'x' in '0xFF') to its base.
+var integerPrefixes = map[rune]int{
+	'x': 16, 'X': 16,
+	'o': 8, 'O': 8,
+	'b': 2, 'B': 2,
+}
+
+// baseNames are the names of the bases in the errors, e.g. 'hexadecimal literal "0x" has no digits'.
+var baseNames = map[int]string{
+	16: "hexadecimal",
+	8:  "octal",
+	2:  "binary",
+}
+
 // readNumber reads in a number and advances our lexers' positions until it encounters a non-number character.
-func (l *Lexer) readNumber() string {
+// A number with a fraction ('3.14') or an exponent ('1e9', '1.5e-3') is a token.FLOAT, otherwise it's a token.INT.
+// A digit must follow the '.', e.g. '1.' is a malformed float literal.
+// An integer can have the prefix of another base: '0x' (hexadecimal), '0o' (octal) or '0b' (binary), e.g. '0xFF'.
+// The digits can be separated by '_', e.g. '1_000_000', each '_' must be between two digits, or right after a prefix (e.g. '0x_FF').
+// A malformed number (e.g. '1.', '1e', '0x', '0b102' or '1__0') results in a token.ILLEGAL describing the problem.
+func (l *Lexer) readNumber() *token.Token {
 	position := l.position
-	for isDigit(l.ch) {
+	if l.ch == '0' {
+		if base, ok := integerPrefixes[l.peekNextChar()]; ok {
+			return l.readPrefixedInteger(base)
+		}
+	}
+
+	lt := token.LexicalType(token.INT)
+	l.readDigits()
+	if l.ch == '.' && !isDigit(l.peekNextChar()) {
+		// The '.' is part of the malformed literal, there's no token it could start on its own.
 		l.readChar()
+		number := l.slice(position, l.position)
+		return token.New(token.ILLEGAL, fmt.Sprintf("malformed float literal %q", number))
 	}
-	currNumStr := l.input[position:l.position]
-	return currNumStr
+	if l.ch == '.' {
+		lt = token.FLOAT
+		l.readChar()
+		l.readDigits()
+	}
+	if l.ch == 'e' || l.ch == 'E' {
+		lt = token.FLOAT
+		l.readChar()
+		if l.ch == '+' || l.ch == '-' {
+			l.readChar()
+		}
+		if !isDigit(l.ch) {
+			number := l.slice(position, l.position)
+			return token.New(token.ILLEGAL, fmt.Sprintf("exponent has no digits in number literal %q", number))
+		}
+		l.readDigits()
+	}
+
+	number := l.slice(position, l.position)
+	if !separatesDigits(number, 10) {
+		return token.New(token.ILLEGAL, fmt.Sprintf("'_' must separate successive digits in number literal %q", number))
+	}
+	return token.New(lt, number)
 }
 
-// isLetter determines whether an input character is a letter.
-func isLetter(ch byte) bool {
-	// For ASCII, letter a-z lies within [97, 122] and A-Z within [65, 90].
-	// We treat "_" as letter(ASCII: 95), indicating we allow both Camel Case and Snake Case for names of variables or functions.
-	if (65 <= ch && ch <= 90) || (97 <= ch && ch <= 122) || ch == 95 {
-		return true
+// readPrefixedInteger reads in an integer with the prefix of base, e.g. '0xFF', the current char is the '0'.
+// The letters and digits right after the prefix are part of the literal, so an invalid digit (e.g. 'G' in '0xFG') is reported rather than starting another token.
+func (l *Lexer) readPrefixedInteger(base int) *token.Token {
+	position := l.position
+	// Skip the '0' and the letter of the prefix.
+	l.readChar()
+	l.readChar()
+
+	var invalid rune
+	for isDigit(l.ch) || isLetter(l.ch) {
+		if invalid == 0 && l.ch != '_' && digitValue(l.ch) >= base {
+			invalid = l.ch
+		}
+		l.readChar()
 	}
-	return false
+
+	number := l.slice(position, l.position)
+	switch {
+	case strings.Trim(number[2:], "_") == "":
+		return token.New(token.ILLEGAL, fmt.Sprintf("%s literal %q has no digits", baseNames[base], number))
+	case invalid != 0:
+		return token.New(token.ILLEGAL, fmt.Sprintf("invalid digit %q in %s literal %q", invalid, baseNames[base], number))
+	case !separatesDigits(number, base):
+		return token.New(token.ILLEGAL, fmt.Sprintf("'_' must separate successive digits in number literal %q", number))
+	}
+	return token.New(token.INT, number)
 }
 
-// isDigit determines whether an input character is a number.
-func isDigit(ch byte) bool {
-	// For ASCII, letter 0-9 lies between [48, 57].
-	if 48 <= ch && ch <= 57 {
-		return true
+// readDigits advances our lexers' positions past the digits and the '_' separating them at the current char.
+func (l *Lexer) readDigits() {
+	for isDigit(l.ch) || l.ch == '_' {
+		l.readChar()
+	}
+}
+
+// separatesDigits reports whether every '_' in the number literal s is between two digits of base, or right after the prefix of base.
+func separatesDigits(s string, base int) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '_' {
+			continue
+		}
+		afterDigit := i > 0 && digitValue(rune(s[i-1])) < base
+		afterPrefix := base != 10 && i == 2
+		beforeDigit := i+1 < len(s) && digitValue(rune(s[i+1])) < base
+		if !(afterDigit || afterPrefix) || !beforeDigit {
+			return false
+		}
+	}
+	return true
+}
+
+// digitValue returns the value of ch as a digit, up to base 16, e.g. 10 for 'a' or 'A'.
+// It returns 16 if ch isn't a digit, which is invalid in any base.
+func digitValue(ch rune) int {
+	switch {
+	case '0' <= ch && ch <= '9':
+		return int(ch - '0')
+	case 'a' <= ch && ch <= 'f':
+		return int(ch-'a') + 10
+	case 'A' <= ch && ch <= 'F':
+		return int(ch-'A') + 10
+	default:
+		return 16
 	}
-	return false
+}
+
+// isLetter determines whether an input character can start an identifier.
+// It follows the ID_Start rule of Unicode Standard Annex #31, approximated with general categories:
+// letters (L) and letter numbers (Nl).
+// We treat "_" as letter as well, indicating we allow both Camel Case and Snake Case for names of variables or functions.
+func isLetter(ch rune) bool {
+	return ch == '_' || unicode.In(ch, unicode.L, unicode.Nl)
+}
+
+// isIdentifierPart determines whether an input character can continue an identifier.
+// It follows the ID_Continue rule of Unicode Standard Annex #31, approximated with general categories:
+// anything that can start an identifier, plus non-spacing marks (Mn), spacing marks (Mc), decimal numbers (Nd) and connector punctuations (Pc).
+func isIdentifierPart(ch rune) bool {
+	return isLetter(ch) || unicode.In(ch, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc)
+}
+
+// isDigit determines whether an input character is a number.
+// Only ASCII digits make up a number literal.
+func isDigit(ch rune) bool {
+	return '0' <= ch && ch <= '9'
 }
 
 // skipWhiteSpace skips the encounter white space and advances the lexers pointer forward until it encounters a non-white-space character.
 func (l *Lexer) skipWhiteSpace() {
-	white := map[byte]struct{}{
+	white := map[rune]struct{}{
 		' ':  {},
 		'\t': {},
 		'\n': {},
@@ -231,20 +595,140 @@ func (l *Lexer) skipWhiteSpace() {
 	}
 }
 
+// skipTrivia skips the white space and the comments before the next token, and returns the comments in the order they appear.
+// A line comment starts with '//' and runs until the end of the line, a block comment is enclosed by '/*' and '*/' and can be nested.
+// It stops at a comment that has to be returned as a token instead:
+// a token.COMMENT if the lexer emits comments, or a token.ILLEGAL if it's an unterminated block comment, located at its opening '/*'.
+func (l *Lexer) skipTrivia() ([]token.Comment, *token.Token) {
+	var comments []token.Comment
+	for {
+		l.skipWhiteSpace()
+		if l.ch != '/' || (l.peekNextChar() != '/' && l.peekNextChar() != '*') {
+			return comments, nil
+		}
+		// The comment is sliced from input, the content before it isn't needed.
+		l.discard()
+
+		var comment token.Comment
+		if l.peekNextChar() == '/' {
+			comment = l.readLineComment()
+		} else if c, ok := l.readBlockComment(); ok {
+			comment = c
+		} else {
+			tok := token.New(token.ILLEGAL, UnterminatedBlockComment)
+			tok.Span = c.Span
+			return comments, tok
+		}
+
+		if l.emitComments {
+			tok := token.New(token.COMMENT, comment.Text)
+			tok.Span = comment.Span
+			return comments, tok
+		}
+		comments = append(comments, comment)
+	}
+}
+
+// readLineComment reads in a comment starting with '//', the current char ch is the first '/'.
+// After reading, ch is the newline that ends the comment (or EOF), so the newline isn't part of the comment.
+func (l *Lexer) readLineComment() token.Comment {
+	start := l.currentPosition()
+	for l.ch != '\n' && l.ch != 0 {
+		l.readChar()
+	}
+	text := l.slice(start.Offset, l.position)
+
+	return token.Comment{
+		// A comment on a line ending with '\r\n' doesn't include the '\r'.
+		Text: strings.TrimSuffix(text, "\r"),
+		Span: token.Span{Start: start, End: l.currentPosition()},
+	}
+}
+
+// readBlockComment reads in a comment enclosed by '/*' and '*/', the current char ch is the '/' of the opening '/*'.
+// Block comments can be nested, e.g. '/* outer /* inner */ still outer */', so a block of code can be commented out even if it contains comments.
+// After reading, ch is the char after the closing '*/'.
+// It reports false if the input ends before the comment is closed, the comment then runs until EOF.
+func (l *Lexer) readBlockComment() (token.Comment, bool) {
+	start := l.currentPosition()
+	// Skip the opening '/*'.
+	l.readChar()
+	l.readChar()
+
+	depth := 1
+	for depth > 0 && l.ch != 0 {
+		switch {
+		case l.ch == '/' && l.peekNextChar() == '*':
+			depth++
+			l.readChar()
+		case l.ch == '*' && l.peekNextChar() == '/':
+			depth--
+			l.readChar()
+		}
+		l.readChar()
+	}
+
+	comment := token.Comment{
+		Text: l.slice(start.Offset, l.position),
+		Span: token.Span{Start: start, End: l.currentPosition()},
+	}
+	return comment, depth == 0
+}
+
 // peekNextChar looks ahead (at location readPosition) and returns the immediate next character.
 // Returns 0 if there's nothing ahead (EOF).
-func (l *Lexer) peekNextChar() byte {
-	if l.readPosition >= len(l.input) {
+func (l *Lexer) peekNextChar() rune {
+	l.fill()
+	if l.readPosition-l.base >= len(l.input) {
 		return 0
-	} else {
-		return l.input[l.readPosition]
 	}
+	ch, _ := utf8.DecodeRune(l.input[l.readPosition-l.base:])
+	return ch
+}
+
+// fill reads from the reader until at least one whole character after readPosition is loaded into input, or the reader is exhausted.
+// It does nothing for a preloaded input.
+func (l *Lexer) fill() {
+	for l.reader != nil && l.readErr == nil && len(l.input)-(l.readPosition-l.base) < utf8.UTFMax {
+		n, err := l.reader.Read(l.chunk)
+		l.input = append(l.input, l.chunk[:n]...)
+		if err != nil {
+			l.readErr = err
+		}
+	}
+}
+
+// discard drops the part of input before the current char ch, which no token is going to be read from anymore.
+// It does nothing for a preloaded input, the whole input is kept.
+// The dropped bytes are freed once fill outgrows the array of input, only the bytes kept are copied to the new one.
+func (l *Lexer) discard() {
+	if l.reader == nil {
+		return
+	}
+	// Past the end of the input, there's nothing left to keep.
+	n := min(l.position-l.base, len(l.input))
+	l.input = l.input[n:]
+	l.base += n
+}
+
+// slice returns the content between the offsets start and end, which must be in input.
+func (l *Lexer) slice(start, end int) string {
+	return string(l.input[start-l.base : end-l.base])
 }
 
 // Free initializes the lexer.
 func (l *Lexer) Free() {
-	l.input = ""
+	l.filename = ""
+	l.input = nil
+	l.base = 0
+	l.reader = nil
+	l.chunk = nil
+	l.readErr = nil
 	l.position = 0
 	l.readPosition = 0
-	l.ch = byte(rune(0))
+	l.ch = 0
+	l.chWidth = 0
+	l.line = 1
+	l.column = 1
+	l.emitComments = false
 }
@@ -2,13 +2,17 @@ package lexer
 
 import (
 	token "Lisa/lexToken"
+	"errors"
+	"reflect"
+	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 func Test_New(t *testing.T) {
 	testInput := "test"
 	l := New("test")
-	if l.input != testInput {
+	if string(l.input) != testInput {
 		t.Errorf("error initializing lexer - wrong input: expected %s, have %s.\n", testInput, l.input)
 	}
 	if l.position != 0 {
@@ -17,7 +21,7 @@ func Test_New(t *testing.T) {
 	if l.readPosition != 1 {
 		t.Errorf("error initializing lexer - wrong read position: expected %d, have %d.\n", 1, l.readPosition)
 	}
-	if l.ch != testInput[0] {
+	if l.ch != rune(testInput[0]) {
 		t.Errorf("error initializing lexer - wrong character: expected %q, have %q.\n", testInput[0], l.ch)
 	}
 }
@@ -27,10 +31,13 @@ func TestLexer_readChar(t *testing.T) {
 		input            string
 		jumps            int
 		expectedPosition int
-		expectedCurrChar byte
+		expectedCurrChar rune
 	}{
 		{"test 32", 3, 3, 't'},
 		{"32", 3, 3, 0},
+		// 'é' takes two bytes, 'c' starts at byte 3 and 'h' at byte 4.
+		{"éch", 1, 2, 'c'},
+		{"日本語", 2, 6, '語'},
 	}
 	var lex *Lexer
 	for i, tc := range testCases {
@@ -55,6 +62,10 @@ func TestLexer_readIdentifier(t *testing.T) {
 		{"test 32", "test"},
 		{"32", ""},
 		{"test_abc 32", "test_abc"},
+		{"café = 5", "café"},
+		{"x1y2 32", "x1y2"},
+		{"日本語;", "日本語"},
+		{"_ 32", "_"},
 	}
 	var lex *Lexer
 	for i, tc := range testCases {
@@ -68,7 +79,7 @@ func TestLexer_readIdentifier(t *testing.T) {
 
 func Test_isLetter(t *testing.T) {
 	testCases := []struct {
-		ch       byte
+		ch       rune
 		isLetter bool
 	}{
 		{'0', false},
@@ -76,6 +87,12 @@ func Test_isLetter(t *testing.T) {
 		{' ', false},
 		{'A', true},
 		{'_', true},
+		{'é', true},
+		{'λ', true},
+		{'日', true},
+		{'Ⅻ', true},
+		{'٣', false},
+		{'€', false},
 	}
 
 	for i, tc := range testCases {
@@ -88,30 +105,72 @@ func Test_isLetter(t *testing.T) {
 
 func TestLexer_readNumber(t *testing.T) {
 	testCases := []struct {
-		input          string
-		expectedNumber string
+		input           string
+		expectedType    token.LexicalType
+		expectedLiteral string
 	}{
-		{"32 fds", "32"},
-		{"32", "32"},
+		{"32 fds", token.INT, "32"},
+		{"32", token.INT, "32"},
+		{"3.14;", token.FLOAT, "3.14"},
+		{"0.5", token.FLOAT, "0.5"},
+		{"1e9", token.FLOAT, "1e9"},
+		{"1E9", token.FLOAT, "1E9"},
+		{"1.5e-3 ", token.FLOAT, "1.5e-3"},
+		{"2.5E+10", token.FLOAT, "2.5E+10"},
+		// A digit must follow the '.'.
+		{"1.", token.ILLEGAL, `malformed float literal "1."`},
+		{"1.;", token.ILLEGAL, `malformed float literal "1."`},
+		{"1.e5", token.ILLEGAL, `malformed float literal "1."`},
+		{"1_000.", token.ILLEGAL, `malformed float literal "1_000."`},
+		{"1e", token.ILLEGAL, `exponent has no digits in number literal "1e"`},
+		{"1.5e+;", token.ILLEGAL, `exponent has no digits in number literal "1.5e+"`},
+		{"0xFF;", token.INT, "0xFF"},
+		{"0XdeadBEEF", token.INT, "0XdeadBEEF"},
+		{"0o755", token.INT, "0o755"},
+		{"0O17", token.INT, "0O17"},
+		{"0b1010", token.INT, "0b1010"},
+		{"0B1", token.INT, "0B1"},
+		{"0755", token.INT, "0755"},
+		{"1_000_000;", token.INT, "1_000_000"},
+		{"0x_FF_FF", token.INT, "0x_FF_FF"},
+		{"0b_1010_1010", token.INT, "0b_1010_1010"},
+		{"1_000.000_1", token.FLOAT, "1_000.000_1"},
+		{"1e1_0", token.FLOAT, "1e1_0"},
+		{"0x;", token.ILLEGAL, `hexadecimal literal "0x" has no digits`},
+		{"0o_", token.ILLEGAL, `octal literal "0o_" has no digits`},
+		{"0b", token.ILLEGAL, `binary literal "0b" has no digits`},
+		{"0b102", token.ILLEGAL, `invalid digit '2' in binary literal "0b102"`},
+		{"0o78", token.ILLEGAL, `invalid digit '8' in octal literal "0o78"`},
+		{"0xFG + 1", token.ILLEGAL, `invalid digit 'G' in hexadecimal literal "0xFG"`},
+		{"1__0", token.ILLEGAL, `'_' must separate successive digits in number literal "1__0"`},
+		{"1_;", token.ILLEGAL, `'_' must separate successive digits in number literal "1_"`},
+		{"1_.5", token.ILLEGAL, `'_' must separate successive digits in number literal "1_.5"`},
+		{"1_e5", token.ILLEGAL, `'_' must separate successive digits in number literal "1_e5"`},
+		{"0x__F", token.ILLEGAL, `'_' must separate successive digits in number literal "0x__F"`},
+		{"0xF_", token.ILLEGAL, `'_' must separate successive digits in number literal "0xF_"`},
 	}
 	var lex *Lexer
 	for i, tc := range testCases {
 		lex = New(tc.input)
-		re := lex.readNumber()
-		if re != tc.expectedNumber {
-			t.Errorf("tests[%d] - error reading number: expected %s, got %s.\n", i, tc.expectedNumber, re)
+		tok := lex.readNumber()
+		if tok.Type != tc.expectedType {
+			t.Errorf("tests[%d] - error reading number type: expected %s, got %s.\n", i, tc.expectedType, tok.Type)
+		}
+		if tok.Literal != tc.expectedLiteral {
+			t.Errorf("tests[%d] - error reading number: expected %s, got %s.\n", i, tc.expectedLiteral, tok.Literal)
 		}
 	}
 }
 
 func Test_isDigit(t *testing.T) {
 	testCases := []struct {
-		ch      byte
+		ch      rune
 		isDigit bool
 	}{
 		{'0', true},
 		{'a', false},
 		{' ', false},
+		{'٣', false},
 	}
 
 	for i, tc := range testCases {
@@ -168,6 +227,50 @@ func TestLexer_NextToken(t *testing.T) {
 				{expectedType: token.EOF, expectedLiteral: ""},
 			},
 		},
+		{
+			input: `3.14 * 2e3 - 1.;1e+ 2;0xff+1__0;`,
+			expectedParsedResults: []struct {
+				expectedType    token.LexicalType
+				expectedLiteral string
+			}{
+				{expectedType: token.FLOAT, expectedLiteral: "3.14"},
+				{expectedType: token.ASTERISK, expectedLiteral: "*"},
+				{expectedType: token.FLOAT, expectedLiteral: "2e3"},
+				{expectedType: token.MINUS, expectedLiteral: "-"},
+				{expectedType: token.ILLEGAL, expectedLiteral: `malformed float literal "1."`},
+				{expectedType: token.SEMICOLON, expectedLiteral: ";"},
+				// The lexer carries on right after a malformed number.
+				{expectedType: token.ILLEGAL, expectedLiteral: `exponent has no digits in number literal "1e+"`},
+				{expectedType: token.INT, expectedLiteral: "2"},
+				{expectedType: token.SEMICOLON, expectedLiteral: ";"},
+				{expectedType: token.INT, expectedLiteral: "0xff"},
+				{expectedType: token.PLUS, expectedLiteral: "+"},
+				{expectedType: token.ILLEGAL, expectedLiteral: `'_' must separate successive digits in number literal "1__0"`},
+				{expectedType: token.SEMICOLON, expectedLiteral: ";"},
+				{expectedType: token.EOF, expectedLiteral: ""},
+			},
+		},
+		{
+			input: `[1, 2][0:-1];`,
+			expectedParsedResults: []struct {
+				expectedType    token.LexicalType
+				expectedLiteral string
+			}{
+				{expectedType: token.LBRACKET, expectedLiteral: "["},
+				{expectedType: token.INT, expectedLiteral: "1"},
+				{expectedType: token.COMMA, expectedLiteral: ","},
+				{expectedType: token.INT, expectedLiteral: "2"},
+				{expectedType: token.RBRACKET, expectedLiteral: "]"},
+				{expectedType: token.LBRACKET, expectedLiteral: "["},
+				{expectedType: token.INT, expectedLiteral: "0"},
+				{expectedType: token.COLON, expectedLiteral: ":"},
+				{expectedType: token.MINUS, expectedLiteral: "-"},
+				{expectedType: token.INT, expectedLiteral: "1"},
+				{expectedType: token.RBRACKET, expectedLiteral: "]"},
+				{expectedType: token.SEMICOLON, expectedLiteral: ";"},
+				{expectedType: token.EOF, expectedLiteral: ""},
+			},
+		},
 		{
 			input: `var five = 5;
 					var ten = 10;
@@ -226,14 +329,14 @@ func TestLexer_NextToken(t *testing.T) {
 			},
 		},
 		{
-			// even though !-/*5 doesn't make sense, the lexer still has to parse it into token and let other mechanism check if the logic is correct.
-			input: `!-/*5^; 
+			// even though !-/ *5 doesn't make sense, the lexer still has to parse it into token and let other mechanism check if the logic is correct.
+			input: `!-/ *5^; 
    					5 < 10 > 5;`,
 			expectedParsedResults: []struct {
 				expectedType    token.LexicalType
 				expectedLiteral string
 			}{
-				// !-/*5^;
+				// !-/ *5^;
 				{expectedType: token.EXCLAMATION, expectedLiteral: "!"},
 				{expectedType: token.MINUS, expectedLiteral: "-"},
 				{expectedType: token.SLASH, expectedLiteral: "/"},
@@ -384,3 +487,448 @@ func TestLexer_NextToken(t *testing.T) {
 		}
 	}
 }
+
+func TestLexer_TokenPosition(t *testing.T) {
+	input := `var five = 5;
+  five != 10;`
+
+	expectedSpans := []token.Span{
+		// var five = 5;
+		{Start: token.Position{Filename: "main.lisa", Offset: 0, Line: 1, Column: 1}, End: token.Position{Filename: "main.lisa", Offset: 3, Line: 1, Column: 4}},
+		{Start: token.Position{Filename: "main.lisa", Offset: 4, Line: 1, Column: 5}, End: token.Position{Filename: "main.lisa", Offset: 8, Line: 1, Column: 9}},
+		{Start: token.Position{Filename: "main.lisa", Offset: 9, Line: 1, Column: 10}, End: token.Position{Filename: "main.lisa", Offset: 10, Line: 1, Column: 11}},
+		{Start: token.Position{Filename: "main.lisa", Offset: 11, Line: 1, Column: 12}, End: token.Position{Filename: "main.lisa", Offset: 12, Line: 1, Column: 13}},
+		{Start: token.Position{Filename: "main.lisa", Offset: 12, Line: 1, Column: 13}, End: token.Position{Filename: "main.lisa", Offset: 13, Line: 1, Column: 14}},
+
+		// five != 10;
+		{Start: token.Position{Filename: "main.lisa", Offset: 16, Line: 2, Column: 3}, End: token.Position{Filename: "main.lisa", Offset: 20, Line: 2, Column: 7}},
+		{Start: token.Position{Filename: "main.lisa", Offset: 21, Line: 2, Column: 8}, End: token.Position{Filename: "main.lisa", Offset: 23, Line: 2, Column: 10}},
+		{Start: token.Position{Filename: "main.lisa", Offset: 24, Line: 2, Column: 11}, End: token.Position{Filename: "main.lisa", Offset: 26, Line: 2, Column: 13}},
+		{Start: token.Position{Filename: "main.lisa", Offset: 26, Line: 2, Column: 13}, End: token.Position{Filename: "main.lisa", Offset: 27, Line: 2, Column: 14}},
+	}
+
+	l := NewFile("main.lisa", input)
+	for i, expected := range expectedSpans {
+		tok := l.ReadNextToken()
+		if tok.Span != expected {
+			t.Errorf("tests[%d] - error token span of %q: expected %+v, got %+v.\n", i, tok.Literal, expected, tok.Span)
+		}
+	}
+}
+
+func Test_isIdentifierPart(t *testing.T) {
+	testCases := []struct {
+		ch               rune
+		isIdentifierPart bool
+	}{
+		{'a', true},
+		{'0', true},
+		{'٣', true},
+		{'\u0301', true}, // Combining acute accent.
+		{'‿', true},
+		{'-', false},
+		{' ', false},
+		{'€', false},
+	}
+
+	for i, tc := range testCases {
+		re := isIdentifierPart(tc.ch)
+		if re != tc.isIdentifierPart {
+			t.Errorf("tests[%d] - error isIdentifierPart: expected %v, got %v.\n", i, tc.isIdentifierPart, re)
+		}
+	}
+}
+
+func TestLexer_Unicode(t *testing.T) {
+	input := "var café = 5; café€ \xff x;"
+
+	expectedTokens := []struct {
+		expectedType    token.LexicalType
+		expectedLiteral string
+		expectedOffset  int
+	}{
+		{token.VAR, "var", 0},
+		{token.IDENT, "café", 4},
+		{token.ASSIGN, "=", 10},
+		{token.INT, "5", 12},
+		{token.SEMICOLON, ";", 13},
+		{token.IDENT, "café", 15},
+		{token.ILLEGAL, "€", 20},
+		{token.ILLEGAL, "invalid UTF-8 encoding: byte 0xff", 24},
+		{token.IDENT, "x", 26},
+		{token.SEMICOLON, ";", 27},
+		{token.EOF, "", 28},
+	}
+
+	l := New(input)
+	for i, et := range expectedTokens {
+		tok := l.ReadNextToken()
+		if tok.Type != et.expectedType {
+			t.Errorf("tests[%d] - error token type: expected %q, got %q.\n", i, et.expectedType, tok.Type)
+		}
+		if tok.Literal != et.expectedLiteral {
+			t.Errorf("tests[%d] - error token literal value: expected %q, got %q.\n", i, et.expectedLiteral, tok.Literal)
+		}
+		if tok.Pos().Offset != et.expectedOffset {
+			t.Errorf("tests[%d] - error token offset: expected %d, got %d.\n", i, et.expectedOffset, tok.Pos().Offset)
+		}
+	}
+}
+
+func TestNewFromReader(t *testing.T) {
+	inputs := []string{
+		"",
+		`=+(){},;`,
+		`var five = 5;
+		var add = fn(x, y) {
+			return x + y;
+		};
+		var result = add(five, 10);`,
+		`!-/ *5^;
+		5 < 10 > 5 != 3 == 2;`,
+		"var café = 5; 日本語 € \xff x;",
+		`"a\tb" + "\u{1F600}" + "say \"hi\""`,
+		"// leading\nvar x = 1; // trailing\r\n// last",
+		"/* a /* nested */ block */ x /* spanning\nlines */ + /* unterminated",
+		// Long enough to take more than one chunk.
+		strings.Repeat("var longIdentifierName = 1234567890;\n", 500),
+	}
+
+	for i, input := range inputs {
+		// Reading one byte at a time makes sure characters and tokens split across reads are handled.
+		readers := []*Lexer{
+			NewFromReader("main.lisa", strings.NewReader(input)),
+			NewFromReader("main.lisa", iotest.OneByteReader(strings.NewReader(input))),
+			NewFromReader("main.lisa", iotest.DataErrReader(strings.NewReader(input))),
+		}
+
+		for j, rl := range readers {
+			l := NewFile("main.lisa", input)
+			for tokenIdx := 0; ; tokenIdx++ {
+				expected := l.ReadNextToken()
+				tok := rl.ReadNextToken()
+				if !reflect.DeepEqual(tok, expected) {
+					t.Fatalf("tests[%d], reader[%d], token[%d] - error streamed token: expected %+v, got %+v.\n", i, j, tokenIdx, expected, tok)
+				}
+				if expected.Type == token.EOF {
+					break
+				}
+			}
+
+			// Reading past the end keeps returning EOF.
+			if tok := rl.ReadNextToken(); tok.Type != token.EOF {
+				t.Errorf("tests[%d], reader[%d] - error token after EOF: expected %q, got %q.\n", i, j, token.EOF, tok.Type)
+			}
+			if rl.Err() != nil {
+				t.Errorf("tests[%d], reader[%d] - error reading: expected nil, got %v.\n", i, j, rl.Err())
+			}
+		}
+	}
+}
+
+func TestNewFromReader_ReadError(t *testing.T) {
+	readErr := errors.New("disk on fire")
+	l := NewFromReader("main.lisa", &errorAfterReader{data: "var x", err: readErr})
+
+	// The lexer treats the input as ended after the error.
+	expectedTypes := []token.LexicalType{token.VAR, token.IDENT, token.EOF}
+	for i, expectedType := range expectedTypes {
+		tok := l.ReadNextToken()
+		if tok.Type != expectedType {
+			t.Errorf("tests[%d] - error token type: expected %q, got %q.\n", i, expectedType, tok.Type)
+		}
+	}
+	if !errors.Is(l.Err(), readErr) {
+		t.Errorf("error reading: expected %v, got %v.\n", readErr, l.Err())
+	}
+}
+
+// errorAfterReader returns data, then fails with err.
+type errorAfterReader struct {
+	data string
+	err  error
+}
+
+func (r *errorAfterReader) Read(p []byte) (int, error) {
+	if r.data == "" {
+		return 0, r.err
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestLexer_String(t *testing.T) {
+	testCases := []struct {
+		input           string
+		expectedType    token.LexicalType
+		expectedLiteral string
+		expectedEnd     int
+	}{
+		{`"hello world"`, token.STRING, "hello world", 13},
+		{`""`, token.STRING, "", 2},
+		{`"café 日本"`, token.STRING, "café 日本", 14},
+		{`"a\nb\tc"`, token.STRING, "a\nb\tc", 9},
+		{`"say \"hi\" \\o/"`, token.STRING, `say "hi" \o/`, 17},
+		{`"\u{48}\u{1F600}"`, token.STRING, "H😀", 17},
+		{"\"multi\nline\"", token.STRING, "multi\nline", 12},
+		{`"unterminated`, token.ILLEGAL, "unterminated string literal", 14},
+		{`"bad \q escape"`, token.ILLEGAL, `unknown escape sequence in string literal: \q`, 15},
+		{`"\u48"`, token.ILLEGAL, `invalid unicode escape sequence in string literal: expected '{' after \u`, 6},
+		{`"\u{}"`, token.ILLEGAL, `invalid unicode escape sequence in string literal: \u{} should have 1 to 6 hexadecimal digits`, 6},
+		{`"\u{XYZ}"`, token.ILLEGAL, `invalid unicode escape sequence in string literal: \u{XYZ} isn't hexadecimal`, 9},
+		{`"\u{D800}"`, token.ILLEGAL, `invalid unicode escape sequence in string literal: \u{D800} isn't a valid code point`, 10},
+		{`"\u{41"`, token.ILLEGAL, `invalid unicode escape sequence in string literal: expected '}'`, 7},
+		{"\"\xff\"", token.ILLEGAL, "invalid UTF-8 encoding in string literal: byte 0xff", 3},
+		{`"ends with \`, token.ILLEGAL, "unterminated string literal", 13},
+	}
+
+	for i, tc := range testCases {
+		l := New("  " + tc.input)
+		tok := l.ReadNextToken()
+		if tok.Type != tc.expectedType {
+			t.Errorf("tests[%d] - error token type: expected %q, got %q.\n", i, tc.expectedType, tok.Type)
+		}
+		if tok.Literal != tc.expectedLiteral {
+			t.Errorf("tests[%d] - error token literal value: expected %q, got %q.\n", i, tc.expectedLiteral, tok.Literal)
+		}
+		// A valid string keeps its source text.
+		expectedRaw := ""
+		if tc.expectedType == token.STRING {
+			expectedRaw = tc.input
+		}
+		if tok.Raw != expectedRaw {
+			t.Errorf("tests[%d] - error token raw text: expected %q, got %q.\n", i, expectedRaw, tok.Raw)
+		}
+		// The token is located at the opening quote, even if it's unterminated.
+		if tok.Pos().Offset != 2 {
+			t.Errorf("tests[%d] - error token offset: expected %d, got %d.\n", i, 2, tok.Pos().Offset)
+		}
+		if tok.Span.End.Offset != tc.expectedEnd+2 {
+			t.Errorf("tests[%d] - error token end offset: expected %d, got %d.\n", i, tc.expectedEnd+2, tok.Span.End.Offset)
+		}
+		if next := l.ReadNextToken(); next.Type != token.EOF {
+			t.Errorf("tests[%d] - error token after string: expected %q, got %q (%q).\n", i, token.EOF, next.Type, next.Literal)
+		}
+	}
+}
+
+func TestLexer_Comments(t *testing.T) {
+	input := "// leading\n// another\nvar x = 10 / 2; // trailing\r\nx //\n// at the end"
+
+	expected := []struct {
+		expectedType     token.LexicalType
+		expectedComments []string
+	}{
+		{token.VAR, []string{"// leading", "// another"}},
+		{token.IDENT, nil},
+		{token.ASSIGN, nil},
+		{token.INT, nil},
+		// A single '/' is still a division.
+		{token.SLASH, nil},
+		{token.INT, nil},
+		{token.SEMICOLON, nil},
+		{token.IDENT, []string{"// trailing"}},
+		{token.EOF, []string{"//", "// at the end"}},
+	}
+
+	l := New(input)
+	for i, e := range expected {
+		tok := l.ReadNextToken()
+		if tok.Type != e.expectedType {
+			t.Fatalf("tests[%d] - error token type: expected %q, got %q.\n", i, e.expectedType, tok.Type)
+		}
+		if len(tok.LeadingComments) != len(e.expectedComments) {
+			t.Fatalf("tests[%d] - error number of comments: expected %d, got %d.\n", i, len(e.expectedComments), len(tok.LeadingComments))
+		}
+		for j, c := range tok.LeadingComments {
+			if c.Text != e.expectedComments[j] {
+				t.Errorf("tests[%d] - error comment[%d] text: expected %q, got %q.\n", i, j, e.expectedComments[j], c.Text)
+			}
+			if input[c.Span.Start.Offset:c.Span.Start.Offset+2] != "//" {
+				t.Errorf("tests[%d] - error comment[%d] offset: expected the start of the comment, got %d.\n", i, j, c.Span.Start.Offset)
+			}
+		}
+	}
+
+	// The position of a comment, 3 spaces into the third line.
+	l = New("var x = 1;\n\n   // note\nx")
+	for tok := l.ReadNextToken(); tok.Type != token.EOF; tok = l.ReadNextToken() {
+		if len(tok.LeadingComments) == 0 {
+			continue
+		}
+		span := tok.LeadingComments[0].Span
+		if span.Start.Line != 3 || span.Start.Column != 4 || span.End.Column != 11 {
+			t.Errorf("error comment span: expected 3:4 to 3:11, got %s to %s.\n", span.Start, span.End)
+		}
+	}
+}
+
+func TestLexer_BlockComments(t *testing.T) {
+	testCases := []struct {
+		input            string
+		expectedType     token.LexicalType
+		expectedComments []string
+	}{
+		{"/* block */ x", token.IDENT, []string{"/* block */"}},
+		{"/**/x", token.IDENT, []string{"/**/"}},
+		{"/* multi\nline */ x", token.IDENT, []string{"/* multi\nline */"}},
+		{"/* outer /* inner */ still outer */ x", token.IDENT, []string{"/* outer /* inner */ still outer */"}},
+		{"/* a */ // b\n/* c */ x", token.IDENT, []string{"/* a */", "// b", "/* c */"}},
+		{"/* ** / * */ x", token.IDENT, []string{"/* ** / * */"}},
+		{"// /* not a block\nx", token.IDENT, []string{"// /* not a block"}},
+		{"x */", token.IDENT, nil},
+	}
+
+	for i, tc := range testCases {
+		tok := New(tc.input).ReadNextToken()
+		if tok.Type != tc.expectedType {
+			t.Errorf("tests[%d] - error token type: expected %q, got %q (%q).\n", i, tc.expectedType, tok.Type, tok.Literal)
+			continue
+		}
+		if len(tok.LeadingComments) != len(tc.expectedComments) {
+			t.Errorf("tests[%d] - error number of comments: expected %d, got %d.\n", i, len(tc.expectedComments), len(tok.LeadingComments))
+			continue
+		}
+		for j, c := range tok.LeadingComments {
+			if c.Text != tc.expectedComments[j] {
+				t.Errorf("tests[%d] - error comment[%d] text: expected %q, got %q.\n", i, j, tc.expectedComments[j], c.Text)
+			}
+		}
+	}
+}
+
+func TestLexer_UnterminatedBlockComment(t *testing.T) {
+	inputs := []string{
+		"x;\n  /* never closed",
+		"x;\n  /* outer /* inner */ never closed",
+		"x;\n  /*/",
+	}
+
+	for i, input := range inputs {
+		l := New(input)
+		l.ReadNextToken()
+		l.ReadNextToken()
+
+		// The error is located at the opening '/*', which is on line 2, column 3.
+		tok := l.ReadNextToken()
+		if tok.Type != token.ILLEGAL || tok.Literal != "unterminated block comment" {
+			t.Errorf("tests[%d] - error token: expected %q %q, got %q %q.\n", i, token.ILLEGAL, "unterminated block comment", tok.Type, tok.Literal)
+		}
+		if tok.Pos().Line != 2 || tok.Pos().Column != 3 || tok.Pos().Offset != 5 {
+			t.Errorf("tests[%d] - error token position: expected 2:3 (offset 5), got %s (offset %d).\n", i, tok.Pos(), tok.Pos().Offset)
+		}
+		if tok.Span.End.Offset != len(input) {
+			t.Errorf("tests[%d] - error token end offset: expected %d, got %d.\n", i, len(input), tok.Span.End.Offset)
+		}
+		if next := l.ReadNextToken(); next.Type != token.EOF {
+			t.Errorf("tests[%d] - error token after the comment: expected %q, got %q.\n", i, token.EOF, next.Type)
+		}
+	}
+}
+
+func TestLexer_EmitComments(t *testing.T) {
+	input := "// line\nx /* block */ / y; /* open"
+
+	expected := []struct {
+		expectedType    token.LexicalType
+		expectedLiteral string
+	}{
+		{token.COMMENT, "// line"},
+		{token.IDENT, "x"},
+		{token.COMMENT, "/* block */"},
+		{token.SLASH, "/"},
+		{token.IDENT, "y"},
+		{token.SEMICOLON, ";"},
+		{token.ILLEGAL, "unterminated block comment"},
+		{token.EOF, ""},
+	}
+
+	lexers := []*Lexer{
+		New(input, EmitComments()),
+		NewFromReader("main.lisa", iotest.OneByteReader(strings.NewReader(input)), EmitComments()),
+	}
+	for j, l := range lexers {
+		for i, e := range expected {
+			tok := l.ReadNextToken()
+			if tok.Type != e.expectedType || tok.Literal != e.expectedLiteral {
+				t.Errorf("lexer[%d], tests[%d] - error token: expected %q %q, got %q %q.\n", j, i, e.expectedType, e.expectedLiteral, tok.Type, tok.Literal)
+			}
+			if len(tok.LeadingComments) != 0 {
+				t.Errorf("lexer[%d], tests[%d] - error leading comments: expected none, got %v.\n", j, i, tok.LeadingComments)
+			}
+		}
+	}
+
+	// The comment token spans the whole comment.
+	l := New("x /* a\nb */", EmitComments())
+	l.ReadNextToken()
+	comment := l.ReadNextToken()
+	if comment.Span.Start.Offset != 2 || comment.Span.End.Offset != 11 || comment.Span.End.Line != 2 {
+		t.Errorf("error comment span: expected offsets 2 to 11 ending on line 2, got %d to %d ending on line %d.\n", comment.Span.Start.Offset, comment.Span.End.Offset, comment.Span.End.Line)
+	}
+}
+
+// benchmarkInput is a script of about 100KB.
+var benchmarkInput = strings.Repeat(`var add = fn(x, y) {
+	if (x > y) {
+		return x - y;
+	} else {
+		return x + y * 2;
+	}
+};
+var result = add(five, 10);
+`, 1000)
+
+func BenchmarkNew(b *testing.B) {
+	b.SetBytes(int64(len(benchmarkInput)))
+	for i := 0; i < b.N; i++ {
+		l := New(benchmarkInput)
+		for tok := l.ReadNextToken(); tok.Type != token.EOF; tok = l.ReadNextToken() {
+		}
+	}
+}
+
+func BenchmarkNewFromReader(b *testing.B) {
+	b.SetBytes(int64(len(benchmarkInput)))
+	for i := 0; i < b.N; i++ {
+		l := NewFromReader("bench.lisa", strings.NewReader(benchmarkInput))
+		for tok := l.ReadNextToken(); tok.Type != token.EOF; tok = l.ReadNextToken() {
+		}
+	}
+}
+
+// largeTokenInputs are inputs made of a single token of about 4MB, a streaming lexer keeps all of it in memory while reading it.
+var largeTokenInputs = []struct {
+	name  string
+	input string
+}{
+	{"String", `"` + strings.Repeat("abcdefgh", 1<<19) + `"`},
+	{"BlockComment", "/* " + strings.Repeat("abcdefgh", 1<<19) + " */ x"},
+}
+
+func BenchmarkNew_LargeToken(b *testing.B) {
+	for _, bc := range largeTokenInputs {
+		b.Run(bc.name, func(b *testing.B) {
+			b.SetBytes(int64(len(bc.input)))
+			for i := 0; i < b.N; i++ {
+				l := New(bc.input)
+				for tok := l.ReadNextToken(); tok.Type != token.EOF; tok = l.ReadNextToken() {
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkNewFromReader_LargeToken(b *testing.B) {
+	for _, bc := range largeTokenInputs {
+		b.Run(bc.name, func(b *testing.B) {
+			b.SetBytes(int64(len(bc.input)))
+			for i := 0; i < b.N; i++ {
+				l := NewFromReader("bench.lisa", strings.NewReader(bc.input))
+				for tok := l.ReadNextToken(); tok.Type != token.EOF; tok = l.ReadNextToken() {
+				}
+			}
+		})
+	}
+}
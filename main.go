@@ -3,10 +3,117 @@ package main
 import (
 	"Lisa/repl"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
+// Exit codes of the lisa command.
+const (
+	exitOK = 0
+	// exitFailure is for a runtime error of the program, or a file that can't be read or written.
+	exitFailure = 1
+	// exitUsage is for wrong arguments, like the flag package.
+	exitUsage = 2
+	// exitSyntaxError is for a program with syntax errors.
+	exitSyntaxError = 3
+)
+
+const usage = `Lisa is a tool for running and inspecting Lisa programs.
+
+Usage:
+
+	lisa <command> [arguments]
+	lisa file.lisa    same as 'lisa run file.lisa'
+	lisa              same as 'lisa repl'
+
+The commands are:
+
+	run     run a program and print its value
+	repl    start an interactive session
+	tokens  print the tokens of a program
+	ast     print the syntax tree of a program
+	fmt     format source files
+	check   report the syntax errors of programs
+
+A program is read from a file, from stdin if the file is '-', or given on the command line with -e 'program'.
+Run 'lisa <command> -h' for the arguments of a command.
+`
+
+// command runs a subcommand with its arguments, it returns the exit code.
+type command func(args []string, stdin io.Reader, stdout, stderr io.Writer) int
+
+var commands = map[string]command{
+	"run":    runRun,
+	"repl":   runREPL,
+	"tokens": runTokens,
+	"ast":    runAST,
+	"fmt":    runFmt,
+	"check":  runCheck,
+}
+
 func main() {
-	fmt.Printf("This is the Lisa programming language! Type 'QUIT' to quit.\n")
-	repl.Start(os.Stdin, os.Stdout)
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run dispatches the arguments of the lisa command to a subcommand, and returns the exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		return runREPL(nil, stdin, stdout, stderr)
+	}
+
+	name := args[0]
+	if cmd, ok := commands[name]; ok {
+		return cmd(args[1:], stdin, stdout, stderr)
+	}
+
+	switch {
+	case name == "help" || name == "-h" || name == "-help" || name == "--help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	case name == "-" || name == "-e" || !strings.HasPrefix(name, "-"):
+		// A file to run, e.g. 'lisa script.lisa'.
+		return runRun(args, stdin, stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "lisa: unknown command %q\n\n%s", name, usage)
+		return exitUsage
+	}
+}
+
+// runREPL implements 'lisa repl [-mode eval|ast|tokens] [-prompt prompt] [-history file]', which starts an interactive session on stdin.
+func runREPL(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := newFlagSet("repl", "[-mode eval|ast|tokens] [-prompt prompt] [-history file]", stderr)
+	modeName := flags.String("mode", "eval", "what to print for each line: the value (eval), the syntax tree (ast) or the tokens (tokens)")
+	prompt := flags.String("prompt", repl.PROMPT, "the `prompt` printed before reading a line")
+	history := flags.String("history", defaultHistoryFile(), "the `file` the history is saved in when running in a terminal, empty not to save it")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return exitUsage
+	}
+	mode, err := repl.ParseMode(*modeName)
+	if err != nil {
+		fmt.Fprintf(stderr, "lisa repl: %v\n", err)
+		return exitUsage
+	}
+
+	cfg := repl.Config{Prompt: *prompt, HistoryFile: *history, QuitCommand: repl.QUIT, Mode: mode}
+	fmt.Fprintf(stdout, "This is the Lisa programming language! Type '%s' to quit.\n", cfg.QuitCommand)
+	if err := repl.Start(stdin, stdout, cfg); err != nil {
+		fmt.Fprintf(stderr, "lisa repl: %v\n", err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// defaultHistoryFile returns ~/.lisa_history, or an empty string if there's no home directory.
+func defaultHistoryFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".lisa_history")
 }
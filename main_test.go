@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "script.lisa")
+	if err := os.WriteFile(script, []byte("var add = fn(x, y) { x + y; };\nadd(1, 2) * 10;\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	broken := filepath.Join(dir, "broken.lisa")
+	if err := os.WriteFile(broken, []byte("var x = ;\nvar y 1;\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		args           []string
+		stdin          string
+		expectedCode   int
+		expectedStdout string
+		// expectedStderr is a prefix of what's written to stderr.
+		expectedStderr string
+	}{
+		// Running programs.
+		{[]string{"run", script}, "", exitOK, "30\n", ""},
+		{[]string{script}, "", exitOK, "30\n", ""},
+		{[]string{"run", "-"}, `"a" + "b";`, exitOK, "ab\n", ""},
+		{[]string{"-"}, `var x = 1;`, exitOK, "", ""},
+		{[]string{"run", "-e", "1 + 2;"}, "", exitOK, "3\n", ""},
+		{[]string{"-e", "if (1 > 2) { 1; }"}, "", exitOK, "", ""},
+		// An empty program is still a program.
+		{[]string{"run", "-e", ""}, "", exitOK, "", ""},
+		{[]string{"run", "-e", "1 / 0;"}, "", exitFailure, "", "-e: runtime error: division by zero: 1 / 0\n"},
+		{[]string{"run", broken}, "", exitSyntaxError, "", broken + ":1:9: "},
+		{[]string{"run", filepath.Join(dir, "missing.lisa")}, "", exitFailure, "", "lisa run: open "},
+		{[]string{"run"}, "", exitUsage, "", "usage: lisa run"},
+		{[]string{"run", script, script}, "", exitUsage, "", "usage: lisa run"},
+		{[]string{"run", "-e", "1;", script}, "", exitUsage, "", "lisa run: -e can't be used with files"},
+
+		// Inspecting programs.
+		{[]string{"tokens", "-e", "var x = 1; // one"}, "", exitOK, "1:1\tVAR\t\"var\"\n1:5\tIDENT\t\"x\"\n1:7\t=\t\"=\"\n1:9\tINT\t\"1\"\n1:10\t;\t\";\"\n", ""},
+		{[]string{"tokens", "-comments", "-e", "x /* one */"}, "", exitOK, "1:1\tIDENT\t\"x\"\n1:3\tCOMMENT\t\"/* one */\"\n", ""},
+		{[]string{"tokens", "-"}, "x # y", exitSyntaxError, "1:1\tIDENT\t\"x\"\n1:3\tILLEGAL\t\"#\"\n1:5\tIDENT\t\"y\"\n", ""},
+		{[]string{"ast", "-e", "var x = 1 + 2 * 3; -x;"}, "", exitOK, "var x = (1 + (2 * 3));\n(-x);\n", ""},
+		{[]string{"ast", broken}, "", exitSyntaxError, "", broken + ":1:9: "},
+		{[]string{"check", script}, "", exitOK, "", ""},
+		{[]string{"check", script, broken}, "", exitSyntaxError, "", broken + ":1:9: "},
+		{[]string{"check", "-"}, "var = 1;", exitSyntaxError, "", "<stdin>:1:5: "},
+		{[]string{"check", "-e", ""}, "", exitOK, "", ""},
+		{[]string{"fmt", "-e", "var  x=1;"}, "", exitOK, "var x = 1;\n", ""},
+		// The empty program of -e is formatted, not stdin.
+		{[]string{"fmt", "-e", ""}, "var  x=1;", exitOK, "", ""},
+		{[]string{"fmt", "-"}, "var  x=1;", exitOK, "var x = 1;\n", ""},
+
+		// Interactive sessions.
+		{[]string{"repl", "-mode", "ast", "-prompt", "> "}, "1 + 2;\n", exitOK, "This is the Lisa programming language! Type 'QUIT' to quit.\n> (1 + 2);\n> \n", ""},
+		{nil, "2 ^ 3;\nQUIT\n", exitOK, "This is the Lisa programming language! Type 'QUIT' to quit.\nLISA >>> 8\nLISA >>> ", ""},
+		{[]string{"repl", "-mode", "run"}, "", exitUsage, "", "lisa repl: unknown mode \"run\""},
+
+		// Usage.
+		{[]string{"help"}, "", exitOK, usage, ""},
+		{[]string{"-x"}, "", exitUsage, "", "lisa: unknown command \"-x\""},
+		{[]string{"check", "-x"}, "", exitUsage, "", "flag provided but not defined: -x"},
+	}
+
+	for i, tc := range testCases {
+		var stdout, stderr bytes.Buffer
+		code := run(tc.args, strings.NewReader(tc.stdin), &stdout, &stderr)
+		if code != tc.expectedCode {
+			t.Errorf("tests[%d] - error exit code of %q: expected %d, got %d (%s).\n", i, tc.args, tc.expectedCode, code, stderr.String())
+		}
+		if stdout.String() != tc.expectedStdout {
+			t.Errorf("tests[%d] - error stdout of %q: expected %q, got %q.\n", i, tc.args, tc.expectedStdout, stdout.String())
+		}
+		if !strings.HasPrefix(stderr.String(), tc.expectedStderr) || (tc.expectedStderr == "" && stderr.Len() != 0) {
+			t.Errorf("tests[%d] - error stderr of %q: expected %q, got %q.\n", i, tc.args, tc.expectedStderr, stderr.String())
+		}
+	}
+}
+
+func TestRun_ReportsEveryError(t *testing.T) {
+	// Every syntax error is reported, one per line, by the commands that parse a program without running it.
+	for _, command := range []string{"check", "fmt"} {
+		var stdout, stderr bytes.Buffer
+		code := run([]string{command, "-e", "var x = ;\nvar y 1;\nvar z = 3;"}, nil, &stdout, &stderr)
+		if code != exitSyntaxError {
+			t.Errorf("%s - error exit code: expected %d, got %d.\n", command, exitSyntaxError, code)
+		}
+
+		lines := strings.Split(strings.TrimSuffix(stderr.String(), "\n"), "\n")
+		if len(lines) != 2 {
+			t.Errorf("%s - error number of reported errors: expected %d, got %d (%q).\n", command, 2, len(lines), stderr.String())
+			continue
+		}
+		for i, prefix := range []string{"-e:1:9: ", "-e:2:7: "} {
+			if !strings.HasPrefix(lines[i], prefix) {
+				t.Errorf("%s, tests[%d] - error reported error: expected prefix %q, got %q.\n", command, i, prefix, lines[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package object
+
+import "sort"
+
+// Environment keeps track of the values bound to identifiers, e.g. 'x' in 'var x = 5;'.
+type Environment struct {
+	// store maps the name of an identifier to its bound value.
+	store map[string]Object
+	// outer is the enclosing environment, e.g. the environment a function is defined in.
+	outer *Environment
+	// depth is the number of function calls in progress when the environment is created, 0 outside of any call.
+	// Unlike outer, it follows the callers, not where the functions are defined.
+	depth int
+}
+
+// NewEnvironment creates an empty *Environment.
+func NewEnvironment() *Environment {
+	return &Environment{
+		store: make(map[string]Object),
+	}
+}
+
+// NewEnclosedEnvironment creates an empty *Environment enclosed by outer.
+// Identifiers that aren't bound in the new environment are looked up in outer.
+func NewEnclosedEnvironment(outer *Environment) *Environment {
+	env := NewEnvironment()
+	env.outer = outer
+	return env
+}
+
+// NewCallEnvironment creates the empty *Environment of a function call, enclosed by outer, the environment the function is defined in.
+// caller is the environment the function is called from, the new environment is one call deeper than it (see Depth).
+func NewCallEnvironment(outer, caller *Environment) *Environment {
+	env := NewEnclosedEnvironment(outer)
+	env.depth = caller.depth + 1
+	return env
+}
+
+// Depth returns the number of function calls in progress in e, e.g. 1 in the body of a function called from the top level.
+func (e *Environment) Depth() int {
+	return e.depth
+}
+
+// Get returns the value bound to name, looking it up in the enclosing environments if it isn't bound in e.
+// ok is false when name isn't bound in any of them.
+func (e *Environment) Get(name string) (obj Object, ok bool) {
+	obj, ok = e.store[name]
+	if !ok && e.outer != nil {
+		return e.outer.Get(name)
+	}
+	return obj, ok
+}
+
+// Set binds val to name in e (never in the enclosing environments) and returns val.
+func (e *Environment) Set(name string, val Object) Object {
+	e.store[name] = val
+	return val
+}
+
+// Names returns the names bound in e (not in the enclosing environments), sorted.
+func (e *Environment) Names() []string {
+	names := make([]string, 0, len(e.store))
+	for name := range e.store {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,246 @@
+package object
+
+import (
+	"Lisa/ast"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ObjectType is the runtime type of an Object.
+type ObjectType string
+
+const (
+	// INTEGER is the type of an *Integer.
+	INTEGER ObjectType = "INTEGER"
+	// FLOAT is the type of a *Float.
+	FLOAT ObjectType = "FLOAT"
+	// STRING is the type of a *String.
+	STRING ObjectType = "STRING"
+	// BOOLEAN is the type of a *Boolean.
+	BOOLEAN ObjectType = "BOOLEAN"
+	// NULL is the type of a *Null, the absence of a value.
+	NULL ObjectType = "NULL"
+	// RETURN_VALUE is the type of a *ReturnValue, which wraps the value of a 'return' statement.
+	RETURN_VALUE ObjectType = "RETURN_VALUE"
+	// ERROR is the type of an *Error produced at runtime.
+	ERROR ObjectType = "ERROR"
+	// FUNCTION is the type of a *Function.
+	FUNCTION ObjectType = "FUNCTION"
+	// ARRAY is the type of an *Array.
+	ARRAY ObjectType = "ARRAY"
+	// HASH is the type of a *Hash.
+	HASH ObjectType = "HASH"
+)
+
+// Object is every value the evaluator produces when running a Lisa program.
+type Object interface {
+	// Type returns the runtime type of the object.
+	Type() ObjectType
+	// Inspect returns the representation of the object as it's shown to a user.
+	Inspect() string
+}
+
+// Integer is the runtime value of an integer literal, e.g. '5'.
+// An integer has an arbitrary precision: a value out of the range of an int64 is held by Big, the others by Value.
+// NewBigInteger keeps it that way, thus two equal integers are held the same way.
+type Integer struct {
+	Value int64
+	// Big is the value if it's out of the range of an int64, in which case Value is 0, otherwise it's nil.
+	// It must not be modified, it can be shared with other integers.
+	Big *big.Int
+}
+
+// NewBigInteger returns the integer of value b, which is held by Value if it's in the range of an int64.
+// b must not be modified afterward.
+func NewBigInteger(b *big.Int) *Integer {
+	if b.IsInt64() {
+		return &Integer{Value: b.Int64()}
+	}
+	return &Integer{Big: b}
+}
+
+// BigInt returns the value of the integer as a *big.Int, which must not be modified.
+func (i *Integer) BigInt() *big.Int {
+	if i.Big != nil {
+		return i.Big
+	}
+	return big.NewInt(i.Value)
+}
+
+func (i *Integer) Type() ObjectType { return INTEGER }
+func (i *Integer) Inspect() string {
+	if i.Big != nil {
+		return i.Big.String()
+	}
+	return strconv.FormatInt(i.Value, 10)
+}
+
+// Float is the runtime value of a float literal, e.g. '3.14', or of arithmetic involving one.
+type Float struct {
+	Value float64
+}
+
+func (f *Float) Type() ObjectType { return FLOAT }
+
+// Inspect of a float is the shortest representation that reads back as the same value.
+// It always has a '.' or an exponent, e.g. '2.0' rather than '2', so a float isn't mistaken for an integer, unless it's infinite or NaN.
+func (f *Float) Inspect() string {
+	s := strconv.FormatFloat(f.Value, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".eIN") {
+		s += ".0"
+	}
+	return s
+}
+
+// String is the runtime value of a string literal, e.g. '"hello"'.
+type String struct {
+	Value string
+}
+
+func (s *String) Type() ObjectType { return STRING }
+func (s *String) Inspect() string  { return s.Value }
+
+// Boolean is the runtime value of 'true' or 'false'.
+type Boolean struct {
+	Value bool
+}
+
+func (b *Boolean) Type() ObjectType { return BOOLEAN }
+func (b *Boolean) Inspect() string  { return fmt.Sprintf("%t", b.Value) }
+
+// Null represents the absence of a value, e.g. the value of a statement that produces nothing.
+type Null struct{}
+
+func (n *Null) Type() ObjectType { return NULL }
+func (n *Null) Inspect() string  { return "null" }
+
+// ReturnValue wraps the value of a 'return' statement.
+// The evaluator checks for it to stop evaluating the rest of the statements and unwraps it once it reaches the top.
+type ReturnValue struct {
+	Value Object
+}
+
+func (r *ReturnValue) Type() ObjectType { return RETURN_VALUE }
+func (r *ReturnValue) Inspect() string  { return r.Value.Inspect() }
+
+// Error is a runtime error, e.g. applying '-' to a boolean.
+// Like a ReturnValue, an Error stops the evaluation of the rest of the program.
+type Error struct {
+	Message string
+}
+
+func (e *Error) Type() ObjectType { return ERROR }
+func (e *Error) Inspect() string  { return "ERROR: " + e.Message }
+
+// Function is the runtime value of a function literal.
+// Env is the environment the function is defined in, which lets the function access the identifiers bound there (closure).
+type Function struct {
+	Parameters []*ast.IdentifierExpression
+	Body       *ast.BlockStatement
+	Env        *Environment
+}
+
+func (f *Function) Type() ObjectType { return FUNCTION }
+func (f *Function) Inspect() string {
+	params := make([]string, 0, len(f.Parameters))
+	for _, p := range f.Parameters {
+		params = append(params, p.Value)
+	}
+	return fmt.Sprintf("fn(%s) { ... }", strings.Join(params, ", "))
+}
+
+// Array is the runtime value of an array literal, e.g. '[1, 2, 3]'.
+type Array struct {
+	Elements []Object
+}
+
+func (a *Array) Type() ObjectType { return ARRAY }
+func (a *Array) Inspect() string {
+	elements := make([]string, 0, len(a.Elements))
+	for _, e := range a.Elements {
+		elements = append(elements, e.Inspect())
+	}
+	return fmt.Sprintf("[%s]", strings.Join(elements, ", "))
+}
+
+// HashKey identifies a key of a Hash.
+// Two objects of the same type and value have the same HashKey, e.g. two different *String of "a".
+// It holds the value itself rather than a hash of it, thus two different keys never collide.
+type HashKey struct {
+	Type ObjectType
+	// Value is the value of an integer in the range of an int64, or of a boolean.
+	Value uint64
+	// Text is the value of a string, or the decimal value of an integer out of the range of an int64.
+	Text string
+}
+
+// Hashable is implemented by the objects that can be used as keys of a Hash: integers, booleans and strings.
+type Hashable interface {
+	Object
+	HashKey() HashKey
+}
+
+// HashKey of an integer out of the range of an int64 is its decimal value, which can't be the key of an int64 since its Text is empty.
+func (i *Integer) HashKey() HashKey {
+	if i.Big == nil {
+		return HashKey{Type: i.Type(), Value: uint64(i.Value)}
+	}
+	return HashKey{Type: i.Type(), Text: i.Big.String()}
+}
+
+func (b *Boolean) HashKey() HashKey {
+	var value uint64
+	if b.Value {
+		value = 1
+	}
+	return HashKey{Type: b.Type(), Value: value}
+}
+
+func (s *String) HashKey() HashKey {
+	return HashKey{Type: s.Type(), Text: s.Value}
+}
+
+// HashPair is a key of a Hash along with its value, the key is kept so it can be shown.
+type HashPair struct {
+	Key   Hashable
+	Value Object
+}
+
+// Hash is the runtime value of a hash literal, e.g. '{"a": 1, 2: true}'.
+type Hash struct {
+	Pairs map[HashKey]HashPair
+	// Keys are the keys of Pairs in the order they're first set, thus Inspect shows the pairs in a stable order.
+	Keys []HashKey
+}
+
+// NewHash creates an empty *Hash.
+func NewHash() *Hash {
+	return &Hash{Pairs: make(map[HashKey]HashPair)}
+}
+
+// Get returns the value of key, and reports whether key is set.
+func (h *Hash) Get(key Hashable) (Object, bool) {
+	pair, ok := h.Pairs[key.HashKey()]
+	return pair.Value, ok
+}
+
+// Set sets the value of key, replacing the previous one if any.
+func (h *Hash) Set(key Hashable, value Object) {
+	hashKey := key.HashKey()
+	if _, ok := h.Pairs[hashKey]; !ok {
+		h.Keys = append(h.Keys, hashKey)
+	}
+	h.Pairs[hashKey] = HashPair{Key: key, Value: value}
+}
+
+func (h *Hash) Type() ObjectType { return HASH }
+func (h *Hash) Inspect() string {
+	pairs := make([]string, 0, len(h.Keys))
+	for _, key := range h.Keys {
+		pair := h.Pairs[key]
+		pairs = append(pairs, pair.Key.Inspect()+": "+pair.Value.Inspect())
+	}
+	return fmt.Sprintf("{%s}", strings.Join(pairs, ", "))
+}
@@ -0,0 +1,173 @@
+package object
+
+import (
+	"Lisa/ast"
+	token "Lisa/lexToken"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestObject_Inspect(t *testing.T) {
+	testCases := []struct {
+		obj             Object
+		expectedType    ObjectType
+		expectedInspect string
+	}{
+		{&Integer{Value: -5}, INTEGER, "-5"},
+		{&Integer{Big: new(big.Int).Lsh(big.NewInt(-1), 64)}, INTEGER, "-18446744073709551616"},
+		{&String{Value: "hello"}, STRING, "hello"},
+		{&Float{Value: 3.14}, FLOAT, "3.14"},
+		{&Float{Value: 2}, FLOAT, "2.0"},
+		{&Float{Value: -0.5}, FLOAT, "-0.5"},
+		{&Float{Value: 1.5e-30}, FLOAT, "1.5e-30"},
+		{&Float{Value: 1e21}, FLOAT, "1e+21"},
+		{&Float{Value: math.Inf(1)}, FLOAT, "+Inf"},
+		{&Boolean{Value: true}, BOOLEAN, "true"},
+		{&Null{}, NULL, "null"},
+		{&ReturnValue{Value: &Integer{Value: 10}}, RETURN_VALUE, "10"},
+		{&Error{Message: "identifier not found: x"}, ERROR, "ERROR: identifier not found: x"},
+		{&Function{Parameters: []*ast.IdentifierExpression{
+			{Token: token.New(token.IDENT, "x"), Value: "x"},
+			{Token: token.New(token.IDENT, "y"), Value: "y"},
+		}}, FUNCTION, "fn(x, y) { ... }"},
+		{&Array{Elements: []Object{&Integer{Value: 1}, &String{Value: "two"}}}, ARRAY, "[1, two]"},
+		{&Array{}, ARRAY, "[]"},
+		{NewHash(), HASH, "{}"},
+	}
+
+	for i, tc := range testCases {
+		if tc.obj.Type() != tc.expectedType {
+			t.Errorf("tests[%d] - error object type: expected %s, got %s.\n", i, tc.expectedType, tc.obj.Type())
+		}
+		if tc.obj.Inspect() != tc.expectedInspect {
+			t.Errorf("tests[%d] - error inspecting object: expected %q, got %q.\n", i, tc.expectedInspect, tc.obj.Inspect())
+		}
+	}
+}
+
+func TestHashKey(t *testing.T) {
+	testCases := []struct {
+		a, b     Hashable
+		expected bool
+	}{
+		{&String{Value: "Hello World"}, &String{Value: "Hello World"}, true},
+		{&String{Value: "Hello"}, &String{Value: "World"}, false},
+		{&Integer{Value: 1}, &Integer{Value: 1}, true},
+		{&Integer{Value: 1}, &Integer{Value: -1}, false},
+		{&Boolean{Value: true}, &Boolean{Value: true}, true},
+		{&Boolean{Value: true}, &Boolean{Value: false}, false},
+		{&Integer{Big: new(big.Int).Lsh(big.NewInt(1), 64)}, &Integer{Big: new(big.Int).Lsh(big.NewInt(1), 64)}, true},
+		{&Integer{Big: new(big.Int).Lsh(big.NewInt(1), 64)}, &Integer{Big: new(big.Int).Lsh(big.NewInt(-1), 64)}, false},
+		// A big integer isn't a hash of its value, which could be the one of an int64.
+		{&Integer{Big: new(big.Int).Lsh(big.NewInt(1), 64)}, &Integer{Value: 554774489934347788}, false},
+		// The same value of different types are different keys.
+		{&Integer{Value: 1}, &Boolean{Value: true}, false},
+		{&Integer{Value: 0}, &String{Value: ""}, false},
+	}
+
+	for i, tc := range testCases {
+		if got := tc.a.HashKey() == tc.b.HashKey(); got != tc.expected {
+			t.Errorf("tests[%d] - error comparing hash keys of %s and %s: expected %t, got %t.\n", i, tc.a.Inspect(), tc.b.Inspect(), tc.expected, got)
+		}
+	}
+}
+
+func TestNewBigInteger(t *testing.T) {
+	testCases := []struct {
+		value       *big.Int
+		expectedBig bool
+	}{
+		{big.NewInt(0), false},
+		{big.NewInt(math.MaxInt64), false},
+		{big.NewInt(math.MinInt64), false},
+		{new(big.Int).Add(big.NewInt(math.MaxInt64), big.NewInt(1)), true},
+		{new(big.Int).Sub(big.NewInt(math.MinInt64), big.NewInt(1)), true},
+	}
+
+	for i, tc := range testCases {
+		integer := NewBigInteger(tc.value)
+		if (integer.Big != nil) != tc.expectedBig {
+			t.Errorf("tests[%d] - error integer held by Big: expected %t, got %t.\n", i, tc.expectedBig, integer.Big != nil)
+		}
+		if integer.Inspect() != tc.value.String() {
+			t.Errorf("tests[%d] - error inspecting integer: expected %s, got %s.\n", i, tc.value, integer.Inspect())
+		}
+		if integer.BigInt().Cmp(tc.value) != 0 {
+			t.Errorf("tests[%d] - error BigInt: expected %s, got %s.\n", i, tc.value, integer.BigInt())
+		}
+	}
+}
+
+func TestHash_GetSet(t *testing.T) {
+	h := NewHash()
+	h.Set(&String{Value: "b"}, &Integer{Value: 1})
+	h.Set(&Integer{Value: 2}, &Boolean{Value: true})
+	// Setting a key again replaces the value, but keeps the position of the pair.
+	h.Set(&String{Value: "b"}, &Integer{Value: 3})
+
+	if obj, ok := h.Get(&String{Value: "b"}); !ok || obj.Inspect() != "3" {
+		t.Errorf("error getting key b: expected %q, got %v.\n", "3", obj)
+	}
+	if obj, ok := h.Get(&String{Value: "c"}); ok {
+		t.Errorf("error getting unset key c: expected not ok, got %v.\n", obj)
+	}
+	if h.Inspect() != "{b: 3, 2: true}" {
+		t.Errorf("error inspecting hash: expected %q, got %q.\n", "{b: 3, 2: true}", h.Inspect())
+	}
+}
+
+func TestEnvironment_GetSet(t *testing.T) {
+	env := NewEnvironment()
+	if _, ok := env.Get("x"); ok {
+		t.Errorf("error getting unbound identifier: expected not ok, got ok.\n")
+	}
+
+	env.Set("x", &Integer{Value: 5})
+	obj, ok := env.Get("x")
+	if !ok {
+		t.Fatalf("error getting bound identifier: expected ok, got not ok.\n")
+	}
+	if obj.Inspect() != "5" {
+		t.Errorf("error getting bound identifier: expected %q, got %q.\n", "5", obj.Inspect())
+	}
+}
+
+func TestEnvironment_Enclosed(t *testing.T) {
+	outer := NewEnvironment()
+	outer.Set("x", &Integer{Value: 5})
+	inner := NewEnclosedEnvironment(outer)
+	inner.Set("y", &Integer{Value: 10})
+
+	if obj, ok := inner.Get("x"); !ok || obj.Inspect() != "5" {
+		t.Errorf("error getting identifier from outer environment: expected %q, got %v.\n", "5", obj)
+	}
+	if _, ok := outer.Get("y"); ok {
+		t.Errorf("error getting identifier bound in inner environment: expected not ok, got ok.\n")
+	}
+}
+
+func TestEnvironment_Names(t *testing.T) {
+	outer := NewEnvironment()
+	outer.Set("z", &Integer{Value: 1})
+	env := NewEnclosedEnvironment(outer)
+	if names := env.Names(); len(names) != 0 {
+		t.Errorf("error names of an empty environment: expected none, got %v.\n", names)
+	}
+
+	env.Set("b", &Integer{Value: 2})
+	env.Set("a", &Integer{Value: 3})
+	env.Set("b", &Integer{Value: 4})
+
+	// The names of the outer environment aren't included.
+	expected := []string{"a", "b"}
+	names := env.Names()
+	if len(names) != len(expected) {
+		t.Fatalf("error length of names: expected %d, got %d (%v).\n", len(expected), len(names), names)
+	}
+	for i, name := range names {
+		if name != expected[i] {
+			t.Errorf("tests[%d] - error name: expected %q, got %q.\n", i, expected[i], name)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package parser
+
+import (
+	token "Lisa/lexToken"
+	"fmt"
+	"sort"
+)
+
+// ErrorCode classifies a ParseError, so tools can tell errors apart without matching the message.
+type ErrorCode string
+
+const (
+	// ErrUnexpectedToken means the next token isn't of the expected type, e.g. a missing ';'.
+	ErrUnexpectedToken ErrorCode = "unexpected-token"
+	// ErrNoPrefixParseFn means the token can't start an expression, e.g. ')' in 'var x = );'.
+	ErrNoPrefixParseFn ErrorCode = "no-prefix-parse-fn"
+	// ErrInvalidInteger means an integer literal can't be converted to its value.
+	ErrInvalidInteger ErrorCode = "invalid-integer"
+	// ErrInvalidFloat means a float literal can't be converted to its value, e.g. it's out of the range of a float64.
+	ErrInvalidFloat ErrorCode = "invalid-float"
+	// ErrUnterminatedBlock means the input ends before the '}' of a block.
+	ErrUnterminatedBlock ErrorCode = "unterminated-block"
+	// ErrIllegalToken means the lexer found a problem in the source, e.g. an unterminated string.
+	ErrIllegalToken ErrorCode = "illegal-token"
+)
+
+// ParseError is an error found while parsing, located at the token that causes it.
+type ParseError struct {
+	// Code classifies the error.
+	Code ErrorCode
+	// Pos is where the error is located in the source code.
+	Pos token.Position
+	// Expected is the token types that are expected in place of Actual, empty if the error isn't about a missing token type.
+	Expected []token.LexicalType
+	// Actual is the token that causes the error.
+	Actual *token.Token
+	// Message describes the error.
+	Message string
+}
+
+// Error returns the message prefixed with the position, e.g. 'main.lisa:1:9: error ...'.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Message)
+}
+
+// ErrorList is a list of *ParseError.
+// The zero value is an empty ErrorList ready to use.
+type ErrorList []*ParseError
+
+// Len, Less and Swap implement sort.Interface, errors are ordered by position, then code, then message.
+func (el ErrorList) Len() int      { return len(el) }
+func (el ErrorList) Swap(i, j int) { el[i], el[j] = el[j], el[i] }
+func (el ErrorList) Less(i, j int) bool {
+	e, f := el[i], el[j]
+	if e.Pos.Filename != f.Pos.Filename {
+		return e.Pos.Filename < f.Pos.Filename
+	}
+	if e.Pos.Offset != f.Pos.Offset {
+		return e.Pos.Offset < f.Pos.Offset
+	}
+	if e.Code != f.Code {
+		return e.Code < f.Code
+	}
+	return e.Message < f.Message
+}
+
+// Sort sorts the list in place.
+func (el ErrorList) Sort() {
+	sort.Sort(el)
+}
+
+// RemoveMultiples sorts the list and removes the errors that have the same position and message as the one before them.
+func (el *ErrorList) RemoveMultiples() {
+	el.Sort()
+
+	deduplicated := (*el)[:0]
+	for i, e := range *el {
+		if i > 0 {
+			last := deduplicated[len(deduplicated)-1]
+			if last.Pos == e.Pos && last.Message == e.Message {
+				continue
+			}
+		}
+		deduplicated = append(deduplicated, e)
+	}
+	*el = deduplicated
+}
+
+// Error implements the error interface, it returns the first error and how many more there are.
+func (el ErrorList) Error() string {
+	switch len(el) {
+	case 0:
+		return "no errors"
+	case 1:
+		return el[0].Error()
+	default:
+		return fmt.Sprintf("%s (and %d more errors)", el[0], len(el)-1)
+	}
+}
+
+// Err returns the list as an error, nil if the list is empty.
+func (el ErrorList) Err() error {
+	if len(el) == 0 {
+		return nil
+	}
+	return el
+}
+
+// Strings returns the message of each error, prefixed with its position.
+func (el ErrorList) Strings() []string {
+	messages := make([]string, 0, len(el))
+	for _, e := range el {
+		messages = append(messages, e.Error())
+	}
+	return messages
+}
@@ -0,0 +1,85 @@
+package parser
+
+import (
+	token "Lisa/lexToken"
+	"Lisa/lexer"
+	"errors"
+	"testing"
+)
+
+func TestParser_ParseErrors(t *testing.T) {
+	l := lexer.NewFile("main.lisa", "var x 5;")
+	p := New(l)
+	p.ParseProgram()
+
+	errs := p.ParseErrors()
+	if len(errs) != 1 {
+		t.Fatalf("Error length of expected errors, expected %d, got %d: %v.", 1, len(errs), errs)
+	}
+
+	e := errs[0]
+	if e.Code != ErrUnexpectedToken {
+		t.Errorf("Error code: expected %s, got %s.", ErrUnexpectedToken, e.Code)
+	}
+	if e.Pos.String() != "main.lisa:1:7" {
+		t.Errorf("Error position: expected %s, got %s.", "main.lisa:1:7", e.Pos)
+	}
+	if len(e.Expected) != 1 || e.Expected[0] != token.ASSIGN {
+		t.Errorf("Error expected types: expected [%s], got %v.", token.ASSIGN, e.Expected)
+	}
+	if e.Actual.Type != token.INT || e.Actual.Literal != "5" {
+		t.Errorf("Error actual token: expected INT(5), got %s(%s).", e.Actual.Type, e.Actual.Literal)
+	}
+
+	// The string view is the same as the errors.
+	if p.Errors()[0] != e.Error() {
+		t.Errorf("Error string view: expected %q, got %q.", e.Error(), p.Errors()[0])
+	}
+}
+
+func TestErrorList(t *testing.T) {
+	newErr := func(line, column int, code ErrorCode, msg string) *ParseError {
+		return &ParseError{
+			Code:    code,
+			Pos:     token.Position{Filename: "main.lisa", Offset: line*100 + column, Line: line, Column: column},
+			Message: msg,
+		}
+	}
+
+	var el ErrorList
+	if el.Err() != nil {
+		t.Errorf("Error empty list: expected nil error, got %v.", el.Err())
+	}
+
+	el = ErrorList{
+		newErr(2, 1, ErrUnexpectedToken, "second"),
+		newErr(1, 5, ErrNoPrefixParseFn, "first"),
+		newErr(2, 1, ErrUnexpectedToken, "second"),
+		newErr(1, 5, ErrIllegalToken, "also first"),
+	}
+
+	el.RemoveMultiples()
+	expectedMessages := []string{
+		"main.lisa:1:5: also first",
+		"main.lisa:1:5: first",
+		"main.lisa:2:1: second",
+	}
+	if len(el) != len(expectedMessages) {
+		t.Fatalf("Error length after removing multiples: expected %d, got %d: %v.", len(expectedMessages), len(el), el.Strings())
+	}
+	for i, msg := range el.Strings() {
+		if msg != expectedMessages[i] {
+			t.Errorf("tests[%d] - error message: expected %q, got %q.", i, expectedMessages[i], msg)
+		}
+	}
+
+	expectedErr := "main.lisa:1:5: also first (and 2 more errors)"
+	if el.Error() != expectedErr {
+		t.Errorf("Error message of list: expected %q, got %q.", expectedErr, el.Error())
+	}
+
+	var target ErrorList
+	if !errors.As(el.Err(), &target) || len(target) != 3 {
+		t.Errorf("Error unwrapping list: expected an ErrorList of %d errors, got %v.", 3, target)
+	}
+}
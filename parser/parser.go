@@ -4,8 +4,11 @@ import (
 	"Lisa/ast"
 	token "Lisa/lexToken"
 	"Lisa/lexer"
+	"errors"
 	"fmt"
+	"math/big"
 	"strconv"
+	"strings"
 )
 
 const (
@@ -19,17 +22,48 @@ const (
 	LESSGREATER
 	// SUM is the order of a '+' operator
 	SUM
+	// PRODUCT is the order of a '*' operator
 	PRODUCT
+	// PREFIX is the order of a prefix operator, e.g. '-' in '-5'
 	PREFIX
+	// EXPONENT is the order of a '^' operator.
+	// It binds tighter than prefix operators, thus '-2 ^ 2' is '-(2 ^ 2)'.
+	EXPONENT
+	// CALL is the order of a function call, e.g. 'add(1, 2)'
 	CALL
+	// INDEX is the order of an index or slice expression, e.g. 'arr[1]' or 'arr[1:3]'.
+	INDEX
 )
 
+// precedences is the table that maps the lexical type of an infix operator to its precedence.
+var precedences = map[token.LexicalType]int{
+	token.EQUAL:       EQUALS,
+	token.NOTEQUAL:    EQUALS,
+	token.LESSTHAN:    LESSGREATER,
+	token.GREATERTHAN: LESSGREATER,
+	token.PLUS:        SUM,
+	token.MINUS:       SUM,
+	token.ASTERISK:    PRODUCT,
+	token.SLASH:       PRODUCT,
+	token.CARET:       EXPONENT,
+	token.LPAREN:      CALL,
+	token.LBRACKET:    INDEX,
+}
+
 // Parser is a component that takes the input data, and builds a data structure, checking for correct syntax in the process.
 // Out parser utilizes a *lexer.Lexer and is responsible for building an *ast.ProgramRoot (which is a tree) from the input.
 type Parser struct {
 	// l is a pointer to an instance of the lexer.
 	l      *lexer.Lexer
-	errors []string
+	errors ErrorList
+	// panicking is set when an error is stored, and reset once the parser synchronizes at the end of the broken statement.
+	// While panicking, further errors are dropped since they're most likely caused by the first one.
+	panicking bool
+	// comments are the comments read so far, in the order they appear.
+	comments []token.Comment
+	// braceDepth is the number of '{' read so far, up to the current token, that aren't closed yet.
+	// It tells synchronize which '}' ends the block the broken statement is in.
+	braceDepth int
 
 	// curToken points to the current token.
 	curToken *token.Token
@@ -46,7 +80,7 @@ type Parser struct {
 func New(l *lexer.Lexer) *Parser {
 	p := &Parser{
 		l:              l,
-		errors:         make([]string, 0),
+		errors:         make(ErrorList, 0),
 		curToken:       nil,
 		nextToken:      nil,
 		prefixParseFns: make(map[token.LexicalType]prefixParseFn),
@@ -65,14 +99,38 @@ func New(l *lexer.Lexer) *Parser {
 	// Register parser functions for parsing expressions.
 	p.registerParserFunctionForPrefix(token.IDENT, p.parseIdentifier)
 	p.registerParserFunctionForPrefix(token.INT, p.parseIntegerLiteral)
+	p.registerParserFunctionForPrefix(token.FLOAT, p.parseFloatLiteral)
+	p.registerParserFunctionForPrefix(token.STRING, p.parseStringLiteral)
+	p.registerParserFunctionForPrefix(token.ILLEGAL, p.parseIllegal)
+	p.registerParserFunctionForPrefix(token.TRUE, p.parseBoolean)
+	p.registerParserFunctionForPrefix(token.FALSE, p.parseBoolean)
 	p.registerParserFunctionForPrefix(token.MINUS, p.parsePrefixExpression)
 	p.registerParserFunctionForPrefix(token.EXCLAMATION, p.parsePrefixExpression)
+	p.registerParserFunctionForPrefix(token.LPAREN, p.parseGroupedExpression)
+	p.registerParserFunctionForPrefix(token.IF, p.parseIfExpression)
+	p.registerParserFunctionForPrefix(token.FUNCTION, p.parseFunctionLiteral)
+	p.registerParserFunctionForPrefix(token.LBRACKET, p.parseArrayLiteral)
+	p.registerParserFunctionForPrefix(token.LBRACE, p.parseHashLiteral)
+
+	for _, tt := range []token.LexicalType{
+		token.PLUS, token.MINUS, token.ASTERISK, token.SLASH, token.CARET,
+		token.EQUAL, token.NOTEQUAL, token.LESSTHAN, token.GREATERTHAN,
+	} {
+		p.registerParserFunctionForInfix(tt, p.parseInfixExpression)
+	}
+	p.registerParserFunctionForInfix(token.LPAREN, p.parseCallExpression)
+	p.registerParserFunctionForInfix(token.LBRACKET, p.parseIndexExpression)
 
 	return p
 }
 
-// Errors returns all the errors occur when parsing an input with a Parser.
+// Errors returns the message of all the errors occur when parsing an input with a Parser, prefixed with their positions.
 func (p *Parser) Errors() []string {
+	return p.errors.Strings()
+}
+
+// ParseErrors returns all the errors occur when parsing an input with a Parser.
+func (p *Parser) ParseErrors() ErrorList {
 	return p.errors
 }
 
@@ -87,41 +145,99 @@ func (p *Parser) ParseProgram() *ast.ProgramRoot {
 	// Determine when to stop the recursive parsing -> When current token type is token.EOF.
 	for !p.curTokenTypeIs(token.EOF) {
 		// Parse the statement one by one from the input and append it to astRoot.
+		// A statement with errors is an *ast.BadStatement, so the rest of the tree is still there.
 		stmt := p.parseStatement()
-		// Nil stmt means that there's something wrong in the given input.
-		if stmt != nil {
-			astRoot.Statements = append(astRoot.Statements, stmt)
-		}
+		astRoot.Statements = append(astRoot.Statements, stmt)
 		p.readNextToken()
 	}
 
+	astRoot.Comments = p.comments
 	return astRoot
 }
 
 // readNextToken helps advances the token in the lexer by 1, simultaneously setting the curToken and nextToken.
+// The comments before the new nextToken are collected, so they end up in the ProgramRoot.
+// token.COMMENT tokens are skipped, the parser never sees them.
 func (p *Parser) readNextToken() {
 	p.curToken = p.nextToken
+	if p.curToken != nil {
+		switch p.curToken.Type {
+		case token.LBRACE:
+			p.braceDepth++
+		case token.RBRACE:
+			p.braceDepth--
+		}
+	}
 	// Get the lexical transformation of character to token.
 	p.nextToken = p.l.ReadNextToken()
+	p.comments = append(p.comments, p.nextToken.LeadingComments...)
+
+	// A lexer that emits comments returns them as tokens, they're collected the same way.
+	for p.nextToken.Type == token.COMMENT {
+		p.comments = append(p.comments, token.Comment{Text: p.nextToken.Literal, Span: p.nextToken.Span})
+		p.nextToken = p.l.ReadNextToken()
+		p.comments = append(p.comments, p.nextToken.LeadingComments...)
+	}
 }
 
 // parseStatement is a helper function that checks the type of the curToken and determine what statement to return to ParseProgram.
 // parseStatement stops when reaches a ';' (type token.SEMICOLON).
+// If the statement is broken, the parser synchronizes at the end of it (see synchronize) and returns an *ast.BadStatement in place of it.
 func (p *Parser) parseStatement() ast.Statement {
+	start := p.curToken
+	// depth is the brace depth around the statement, which can start with the '{' of a hash literal.
+	depth := p.braceDepth
+	if start.Type == token.LBRACE {
+		depth--
+	}
+	var stmt ast.Statement
 
 	// Check what statement we're currently parsing.
 	switch p.curToken.Type {
 	case token.VAR:
-		return p.parseVarStatement()
+		if varStmt := p.parseVarStatement(); varStmt != nil {
+			stmt = varStmt
+		}
 	case token.RETURN:
-		return p.parseReturnStatement()
+		if returnStmt := p.parseReturnStatement(); returnStmt != nil {
+			stmt = returnStmt
+		}
 	default:
-		return p.parseExpressionStatement()
+		if expStmt := p.parseExpressionStatement(); expStmt != nil {
+			stmt = expStmt
+		}
+	}
+
+	if p.panicking || stmt == nil {
+		p.synchronize(depth)
+		return &ast.BadStatement{Token: start, End: p.curToken.Span.End}
+	}
+	return stmt
+}
+
+// synchronize skips the tokens of a broken statement, so the parser can carry on with the next one, and ends the panic mode.
+// depth is the brace depth around the statement (see braceDepth).
+// It stops at the ';' that ends the statement, or right before a '}', a statement keyword (var, return, fn, if) or EOF, thus reading the next token starts a new statement.
+// Braces opened by the statement (e.g. a block or a hash literal) are skipped along with their content, including the ones opened before the error.
+func (p *Parser) synchronize(depth int) {
+	defer func() { p.panicking = false }()
+
+	for !p.curTokenTypeIs(token.EOF) {
+		if p.braceDepth <= depth {
+			if p.curTokenTypeIs(token.SEMICOLON) {
+				return
+			}
+			switch p.nextToken.Type {
+			case token.VAR, token.RETURN, token.FUNCTION, token.IF, token.RBRACE, token.EOF:
+				return
+			}
+		}
+		p.readNextToken()
 	}
 }
 
 // parseVarStatement parses a statement that starts with 'var' and ends with ';', (e.g 'var x = 5;').
-// If there's any elements missing from a standard 'var x = 5;' statement, the parser stores all the error in errors and returns a nil ast.VarStatement.
+// If there's any elements missing from a standard 'var x = 5;' statement, the parser stores the error in errors and returns a nil ast.VarStatement.
 func (p *Parser) parseVarStatement() *ast.VarStatement {
 	var stmtInvalid bool
 
@@ -154,8 +270,14 @@ func (p *Parser) parseVarStatement() *ast.VarStatement {
 		stmtInvalid = true
 	}
 
-	// 4. TODO: Check and assign the expression to stmt.Value.
+	// 4. Advance to the first token of the expression, parse it and assign it to stmt.Value.
 	p.readNextToken()
+	stmt.Value = p.parseExpression(LOWEST)
+	if p.panicking {
+		// The expression is malformed and the error naming the offending token is already stored.
+		// Skip checking the semicolon, it'd only add a misleading error.
+		return nil
+	}
 
 	// 5. Check the semicolon at the end of a var statement.
 	if !p.expectNext(token.SEMICOLON) {
@@ -172,7 +294,7 @@ func (p *Parser) parseVarStatement() *ast.VarStatement {
 }
 
 // parseReturnStatement parses a statement that starts with a 'return' and ends with a ';', (e.g 'return 5;').
-// If there's any elements missing from a standard 'return 5;' statement, the parser stores all the error in errors and returns a nil ast.ReturnStatement.
+// If there's any elements missing from a standard 'return 5;' statement, the parser stores the error in errors and returns a nil ast.ReturnStatement.
 func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	var stmtInvalid bool
 
@@ -182,8 +304,13 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 		ReturnValue: nil,
 	}
 
-	// 2. TODO: Check and assign the expression of the value of return.
+	// 2. Advance to the first token of the expression, parse it and assign it to stmt.ReturnValue.
 	p.readNextToken()
+	stmt.ReturnValue = p.parseExpression(LOWEST)
+	if p.panicking {
+		// The expression is malformed and the error naming the offending token is already stored.
+		return nil
+	}
 
 	// 3. Check the semicolon at the end of a return statement.
 	if !p.expectNext(token.SEMICOLON) {
@@ -198,12 +325,33 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	return stmt
 }
 
+// parseExpressionStatement parses an expression followed by a ';', (e.g 'add(1, 2);').
+// A statement that starts with 'if' ends at the '}' of the if expression, the ';' after it is optional.
 func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 	var stmtInvalid bool
 	stmt := &ast.ExpressionStatement{
 		Token: p.curToken,
 	}
+
+	// The if expression isn't passed to the infix loop of parseExpression,
+	// otherwise the statement on the next line would continue it when it starts with an operator, e.g. '-1', '(x)' or '[x]'.
+	if p.curTokenTypeIs(token.IF) {
+		stmt.Expression = p.parseIfExpression()
+		if p.panicking {
+			return nil
+		}
+		if p.nextTokenTypeIs(token.SEMICOLON) {
+			p.readNextToken()
+		}
+		return stmt
+	}
+
 	stmt.Expression = p.parseExpression(LOWEST)
+	if p.panicking {
+		// The expression is malformed and the error naming the offending token is already stored.
+		return nil
+	}
+
 	if !p.expectNext(token.SEMICOLON) {
 		p.storeNextTokenTypeError(token.SEMICOLON)
 		stmtInvalid = true
@@ -216,24 +364,32 @@ func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 	return stmt
 }
 
+// parseExpression parses an expression with Pratt parsing (top down operator precedence).
+// operatorPrecedence is the precedence of the operator on the left of the expression,
+// the loop keeps folding the parsed expression into infix expressions as long as the next operator binds tighter.
+// A malformed expression results in an *ast.BadExpression.
 func (p *Parser) parseExpression(operatorPrecedence int) ast.Expression {
-	// 1. Integer literals (5;)
-	// 2. Identifiers (foobar;) -> (PrefixParseFn)
-
 	// Fetch the parser function from the pre-registered functions.
-	parseFn := p.prefixParseFns[p.curToken.Type]
-	if parseFn != nil {
-		expression := parseFn()
-		return expression
+	prefix := p.prefixParseFns[p.curToken.Type]
+	if prefix == nil {
+		p.storeNoPrefixParseFnError(p.curToken)
+		return p.badExpression(p.curToken)
 	}
-	// 3. Prefix Operators (-5;)
-	// 4. Infix Operators (5 * 2;)
-	// - Normal binary operators (+, -, *, /)
-	// - Comparison Operators (x >= y; x == y;)
-	// - Grouped parentheses to group and reorder evaluation (2 + (5 * 2);)
-	// 5. Function call expression (foobar();)
-	// 6. Function literals (fn(a, b){return a + b;};)
-	return nil
+	leftExp := prefix()
+
+	// Stop at ';' or an error, otherwise keep going until we reach an operator that binds as loose as (or looser than) the current one.
+	for !p.panicking && !p.nextTokenTypeIs(token.SEMICOLON) && operatorPrecedence < p.nextPrecedence() {
+		infix := p.infixParseFns[p.nextToken.Type]
+		if infix == nil {
+			return leftExp
+		}
+
+		// Advance to the infix operator and let it take the left expression.
+		p.readNextToken()
+		leftExp = infix(leftExp)
+	}
+
+	return leftExp
 }
 
 // parseIdentifier turns the current token from a parser to an *ast.IdentifierExpression, returned as an ast.Expression interface.
@@ -252,20 +408,99 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 		Token: p.curToken,
 	}
 
-	// Convert the token of a token literal to a int64 value.
-	value, err := strconv.Atoi(p.curToken.Literal)
+	// Convert the token of a token literal to a int64 value, or a *big.Int if it's out of the range of an int64.
+	value, bigValue, err := integerValue(p.curToken.Literal)
 	if err != nil {
 		errMsg := fmt.Sprintf("error could not parse %q as integer", p.curToken.Literal)
-		p.storeParseTokenError(errMsg)
-		return nil
+		p.storeParseTokenError(ErrInvalidInteger, p.curToken, errMsg)
+		return p.badExpression(exp.Token)
 	}
 
 	// After successfully reading from the string literal, assign the value to the integer literal expression.
-	exp.Value = int64(value)
+	exp.Value = value
+	exp.Big = bigValue
 
 	return exp
 }
 
+// integerValue converts an integer literal, as the lexer reads it, to its value, e.g. '42', '0xFF', '0o755', '0b1010' or '1_000_000'.
+// A leading '0' without a prefix doesn't make the literal octal, '0755' is 755.
+// The value is returned as an int64 if it's in the range of an int64, otherwise as a *big.Int.
+func integerValue(literal string) (int64, *big.Int, error) {
+	base, digits := 10, literal
+	if len(literal) > 2 && literal[0] == '0' {
+		switch literal[1] {
+		case 'x', 'X':
+			base, digits = 16, literal[2:]
+		case 'o', 'O':
+			base, digits = 8, literal[2:]
+		case 'b', 'B':
+			base, digits = 2, literal[2:]
+		}
+	}
+	digits = strings.ReplaceAll(digits, "_", "")
+
+	value, err := strconv.ParseInt(digits, base, 64)
+	if !errors.Is(err, strconv.ErrRange) {
+		return value, nil, err
+	}
+	bigValue, ok := new(big.Int).SetString(digits, base)
+	if !ok {
+		return 0, nil, err
+	}
+	return 0, bigValue, nil
+}
+
+// parseFloatLiteral turns the current token from a parser to an *ast.FloatLiteral, returned as an ast.Expression interface.
+// This function should be registered when starting a new parser, and should be called when parser encounter a token of type token.FLOAT.
+func (p *Parser) parseFloatLiteral() ast.Expression {
+	exp := &ast.FloatLiteral{
+		Token: p.curToken,
+	}
+
+	// The lexer only produces well-formed literals, thus the conversion only fails when the value is out of range, e.g. '1e400'.
+	value, err := strconv.ParseFloat(p.curToken.Literal, 64)
+	if err != nil {
+		errMsg := fmt.Sprintf("error could not parse %q as float", p.curToken.Literal)
+		p.storeParseTokenError(ErrInvalidFloat, p.curToken, errMsg)
+		return p.badExpression(exp.Token)
+	}
+
+	exp.Value = value
+	return exp
+}
+
+// parseStringLiteral turns the current token from a parser to an *ast.StringLiteral, returned as an ast.Expression interface.
+// This function should be registered when starting a new parser, and should be called when parser encounter a token of type token.STRING.
+func (p *Parser) parseStringLiteral() ast.Expression {
+	return &ast.StringLiteral{
+		Token: p.curToken,
+		Value: p.curToken.Literal,
+	}
+}
+
+// badExpression returns a placeholder for a malformed expression that starts at tok.
+func (p *Parser) badExpression(tok *token.Token) ast.Expression {
+	return &ast.BadExpression{Token: tok}
+}
+
+// parseIllegal stores the problem the lexer found (e.g. an unterminated string), which is the literal of a token.ILLEGAL.
+// The token is replaced with an *ast.BadExpression, so the expression it's part of stays complete.
+func (p *Parser) parseIllegal() ast.Expression {
+	errMsg := fmt.Sprintf("error illegal token: %s.", p.curToken.Literal)
+	p.storeParseTokenError(ErrIllegalToken, p.curToken, errMsg)
+	return p.badExpression(p.curToken)
+}
+
+// parseBoolean turns the current token from a parser to an *ast.BooleanExpression, returned as an ast.Expression interface.
+// This function should be registered when starting a new parser, and should be called when parser encounter a token of type token.TRUE or token.FALSE.
+func (p *Parser) parseBoolean() ast.Expression {
+	return &ast.BooleanExpression{
+		Token: p.curToken,
+		Value: p.curTokenTypeIs(token.TRUE),
+	}
+}
+
 func (p *Parser) parsePrefixExpression() ast.Expression {
 	exp := &ast.PrefixExpression{
 		Operator: p.curToken.Literal,
@@ -278,6 +513,286 @@ func (p *Parser) parsePrefixExpression() ast.Expression {
 	return exp
 }
 
+// parseInfixExpression parses an infix expression (e.g. '5 + 6'), with the already parsed left side of the operator.
+// '^' is right associative, thus '2 ^ 3 ^ 2' is '2 ^ (3 ^ 2)'.
+func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
+	exp := &ast.InfixExpression{
+		Token:    p.curToken,
+		Operator: p.curToken.Literal,
+		Left:     left,
+	}
+
+	precedence := p.curPrecedence()
+	if p.curTokenTypeIs(token.CARET) {
+		// Lowering the precedence by one lets the right side take the next '^' first.
+		precedence--
+	}
+
+	// Advance the pointer of the lexer, now it's the token after the operator.
+	p.readNextToken()
+	exp.Right = p.parseExpression(precedence)
+	return exp
+}
+
+// parseGroupedExpression parses the expression inside a pair of parentheses, e.g. '(5 + 5)' in '(5 + 5) * 2'.
+func (p *Parser) parseGroupedExpression() ast.Expression {
+	lparen := p.curToken
+	p.readNextToken()
+
+	exp := p.parseExpression(LOWEST)
+	if !p.expectNext(token.RPAREN) {
+		p.storeNextTokenTypeError(token.RPAREN)
+		return p.badExpression(lparen)
+	}
+	return exp
+}
+
+// parseIfExpression parses an if expression, e.g. 'if (x > y) { x; } else { y; }'.
+// The 'else' part is optional.
+func (p *Parser) parseIfExpression() ast.Expression {
+	exp := &ast.IfExpression{Token: p.curToken}
+
+	// 1. The condition should be enclosed by parentheses.
+	if !p.expectNext(token.LPAREN) {
+		p.storeNextTokenTypeError(token.LPAREN)
+		return p.badExpression(exp.Token)
+	}
+	p.readNextToken()
+	exp.Condition = p.parseExpression(LOWEST)
+	if !p.expectNext(token.RPAREN) {
+		p.storeNextTokenTypeError(token.RPAREN)
+		return p.badExpression(exp.Token)
+	}
+
+	// 2. The consequence.
+	if !p.expectNext(token.LBRACE) {
+		p.storeNextTokenTypeError(token.LBRACE)
+		return p.badExpression(exp.Token)
+	}
+	exp.Consequence = p.parseBlockStatement()
+
+	// 3. The optional alternative.
+	if p.expectNext(token.ELSE) {
+		if !p.expectNext(token.LBRACE) {
+			p.storeNextTokenTypeError(token.LBRACE)
+			return p.badExpression(exp.Token)
+		}
+		exp.Alternative = p.parseBlockStatement()
+	}
+
+	return exp
+}
+
+// parseFunctionLiteral parses a function literal, e.g. 'fn(x, y) { return x + y; }'.
+func (p *Parser) parseFunctionLiteral() ast.Expression {
+	fn := &ast.FunctionLiteral{Token: p.curToken}
+
+	if !p.expectNext(token.LPAREN) {
+		p.storeNextTokenTypeError(token.LPAREN)
+		return p.badExpression(fn.Token)
+	}
+
+	parameters, ok := p.parseFunctionParameters()
+	if !ok {
+		return p.badExpression(fn.Token)
+	}
+	fn.Parameters = parameters
+
+	if !p.expectNext(token.LBRACE) {
+		p.storeNextTokenTypeError(token.LBRACE)
+		return p.badExpression(fn.Token)
+	}
+	fn.Body = p.parseBlockStatement()
+
+	return fn
+}
+
+// parseFunctionParameters parses the comma separated identifiers after the '(' (the current token) of a function literal.
+// After parsing, the current token is the ')'.
+func (p *Parser) parseFunctionParameters() ([]*ast.IdentifierExpression, bool) {
+	parameters := make([]*ast.IdentifierExpression, 0)
+
+	// No parameters, e.g. 'fn() {}'.
+	if p.expectNext(token.RPAREN) {
+		return parameters, true
+	}
+
+	for {
+		if !p.expectNext(token.IDENT) {
+			p.storeNextTokenTypeError(token.IDENT)
+			return nil, false
+		}
+		parameters = append(parameters, &ast.IdentifierExpression{
+			Token: p.curToken,
+			Value: p.curToken.Literal,
+		})
+
+		if !p.expectNext(token.COMMA) {
+			break
+		}
+	}
+
+	if !p.expectNext(token.RPAREN) {
+		p.storeNextTokenTypeError(token.RPAREN)
+		return nil, false
+	}
+	return parameters, true
+}
+
+// parseCallExpression parses a call expression, e.g. 'add(1, 2)', with the already parsed function on the left of '('.
+func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
+	exp := &ast.CallExpression{
+		Token:    p.curToken,
+		Function: function,
+	}
+
+	arguments, ok := p.parseExpressionList(token.RPAREN)
+	if !ok {
+		return p.badExpression(exp.Token)
+	}
+	exp.Arguments = arguments
+	return exp
+}
+
+// parseExpressionList parses the comma separated expressions after the current token, up to the end token,
+// e.g. the arguments of a call expression ending with ')' or the elements of an array literal ending with ']'.
+// After parsing, the current token is the end token.
+func (p *Parser) parseExpressionList(end token.LexicalType) ([]ast.Expression, bool) {
+	list := make([]ast.Expression, 0)
+
+	// An empty list, e.g. 'add()' or '[]'.
+	if p.expectNext(end) {
+		return list, true
+	}
+
+	p.readNextToken()
+	list = append(list, p.parseExpression(LOWEST))
+	for p.expectNext(token.COMMA) {
+		p.readNextToken()
+		list = append(list, p.parseExpression(LOWEST))
+	}
+
+	if !p.expectNext(end) {
+		p.storeNextTokenTypeError(end)
+		return nil, false
+	}
+	return list, true
+}
+
+// parseArrayLiteral parses an array literal, e.g. '[1, 2, 3]', the current token is the '['.
+func (p *Parser) parseArrayLiteral() ast.Expression {
+	array := &ast.ArrayLiteral{Token: p.curToken}
+
+	elements, ok := p.parseExpressionList(token.RBRACKET)
+	if !ok {
+		return p.badExpression(array.Token)
+	}
+	array.Elements = elements
+	return array
+}
+
+// parseHashLiteral parses a hash literal, e.g. '{"a": 1, 2: true}', the current token is the '{'.
+// A '{' where an expression is expected always starts a hash literal, the blocks of 'if' and 'fn' are parsed by parseBlockStatement,
+// and there's no block statement on its own, thus '{ x; }' isn't ambiguous, it's a malformed hash literal.
+// After parsing, the current token is the '}'.
+func (p *Parser) parseHashLiteral() ast.Expression {
+	hash := &ast.HashLiteral{Token: p.curToken, Pairs: make([]ast.HashPair, 0)}
+
+	// An empty hash, e.g. '{}'.
+	if p.expectNext(token.RBRACE) {
+		return hash
+	}
+
+	for {
+		p.readNextToken()
+		key := p.parseExpression(LOWEST)
+		if !p.expectNext(token.COLON) {
+			p.storeNextTokenTypeError(token.COLON)
+			return p.badExpression(hash.Token)
+		}
+
+		p.readNextToken()
+		value := p.parseExpression(LOWEST)
+		hash.Pairs = append(hash.Pairs, ast.HashPair{Key: key, Value: value})
+
+		if !p.expectNext(token.COMMA) {
+			break
+		}
+	}
+
+	if !p.expectNext(token.RBRACE) {
+		p.storeNextTokenTypeError(token.RBRACE)
+		return p.badExpression(hash.Token)
+	}
+	return hash
+}
+
+// parseIndexExpression parses an index expression, e.g. 'arr[1]', with the already parsed expression on the left of '['.
+// A ':' inside the brackets makes it a slice expression instead, e.g. 'arr[1:3]', where either bound can be omitted, e.g. 'arr[:3]'.
+// After parsing, the current token is the ']'.
+func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
+	lbracket := p.curToken
+
+	var index ast.Expression
+	if !p.nextTokenTypeIs(token.COLON) {
+		p.readNextToken()
+		index = p.parseExpression(LOWEST)
+	}
+
+	if !p.expectNext(token.COLON) {
+		if !p.expectNext(token.RBRACKET) {
+			p.storeNextTokenTypeError(token.RBRACKET)
+			return p.badExpression(lbracket)
+		}
+		return &ast.IndexExpression{Token: lbracket, Left: left, Index: index}
+	}
+
+	exp := &ast.SliceExpression{Token: lbracket, Left: left, Low: index}
+	if !p.nextTokenTypeIs(token.RBRACKET) {
+		p.readNextToken()
+		exp.High = p.parseExpression(LOWEST)
+	}
+	if !p.expectNext(token.RBRACKET) {
+		p.storeNextTokenTypeError(token.RBRACKET)
+		return p.badExpression(lbracket)
+	}
+	return exp
+}
+
+// parseBlockStatement parses the statements between a '{' (the current token) and its matching '}'.
+// After parsing, the current token is the '}'.
+func (p *Parser) parseBlockStatement() *ast.BlockStatement {
+	block := &ast.BlockStatement{
+		Token:      p.curToken,
+		Statements: make([]ast.Statement, 0),
+	}
+
+	p.readNextToken()
+	for !p.curTokenTypeIs(token.RBRACE) && !p.curTokenTypeIs(token.EOF) {
+		stmt := p.parseStatement()
+		if stmt != nil {
+			block.Statements = append(block.Statements, stmt)
+		}
+		p.readNextToken()
+	}
+
+	if p.curTokenTypeIs(token.RBRACE) {
+		block.Rbrace = p.curToken.Pos()
+	}
+	if p.curTokenTypeIs(token.EOF) {
+		errMsg := fmt.Sprintf("error unterminated block starting at %s: expected TYPE(%s), got TYPE(%s).", block.Pos(), token.RBRACE, token.EOF)
+		p.storeError(&ParseError{
+			Code:     ErrUnterminatedBlock,
+			Pos:      p.curToken.Pos(),
+			Expected: []token.LexicalType{token.RBRACE},
+			Actual:   p.curToken,
+			Message:  errMsg,
+		})
+	}
+
+	return block
+}
+
 // curTokenTypeIs checks whether the type of the current token is identical as the given type.
 func (p *Parser) curTokenTypeIs(expectType token.LexicalType) bool {
 	return p.curToken.Type == expectType
@@ -298,13 +813,57 @@ func (p *Parser) expectNext(expectType token.LexicalType) bool {
 	return false
 }
 
+// curPrecedence returns the precedence of the current token, LOWEST if it isn't an infix operator.
+func (p *Parser) curPrecedence() int {
+	if precedence, ok := precedences[p.curToken.Type]; ok {
+		return precedence
+	}
+	return LOWEST
+}
+
+// nextPrecedence returns the precedence of the next token, LOWEST if it isn't an infix operator.
+func (p *Parser) nextPrecedence() int {
+	if precedence, ok := precedences[p.nextToken.Type]; ok {
+		return precedence
+	}
+	return LOWEST
+}
+
+// storeNextTokenTypeError stores an error at the position of the next token, which isn't of the expected type.
 func (p *Parser) storeNextTokenTypeError(expectType token.LexicalType) {
 	errMsg := fmt.Sprintf("error next token type: expected TYPE(%s), got TYPE(%s).", expectType, p.nextToken.Type)
-	p.errors = append(p.errors, errMsg)
+	p.storeError(&ParseError{
+		Code:     ErrUnexpectedToken,
+		Pos:      p.nextToken.Pos(),
+		Expected: []token.LexicalType{expectType},
+		Actual:   p.nextToken,
+		Message:  errMsg,
+	})
+}
+
+// storeNoPrefixParseFnError stores an error at the position of a token that can't start an expression.
+func (p *Parser) storeNoPrefixParseFnError(tok *token.Token) {
+	errMsg := fmt.Sprintf("error unexpected token %q: no prefix parse function for TYPE(%s) found.", tok.Literal, tok.Type)
+	p.storeParseTokenError(ErrNoPrefixParseFn, tok, errMsg)
 }
 
-func (p *Parser) storeParseTokenError(errMsg string) {
-	p.errors = append(p.errors, errMsg)
+// storeParseTokenError stores an error caused by tok, located at the position of tok.
+func (p *Parser) storeParseTokenError(code ErrorCode, tok *token.Token, errMsg string) {
+	p.storeError(&ParseError{
+		Code:    code,
+		Pos:     tok.Pos(),
+		Actual:  tok,
+		Message: errMsg,
+	})
+}
+
+// storeError stores err and enters the panic mode, unless the parser is already panicking.
+func (p *Parser) storeError(err *ParseError) {
+	if p.panicking {
+		return
+	}
+	p.errors = append(p.errors, err)
+	p.panicking = true
 }
 
 type prefixParseFn func() ast.Expression
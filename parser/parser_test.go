@@ -26,6 +26,7 @@ func TestParser_ParseStatement(t *testing.T) {
 		}
 
 		expectedIdentifier := []string{"x", "y", "foo_bar"}
+		expectedValue := []int64{5, 10, 838383}
 
 		for i, stmt := range astRoot.Statements {
 			if stmt.TokenLiteral() != "var" {
@@ -50,7 +51,10 @@ func TestParser_ParseStatement(t *testing.T) {
 				t.Errorf("Error token literal: expected '%s', got '%s'.\n", expectedIdentifier[i], varStmt.Name.Value)
 			}
 
-			// 2. TODO: Check the Value of the expression.
+			// 2. Check the Value of the expression.
+			if !correctIntegerLiteral(t, varStmt.Value, expectedValue[i]) {
+				t.Errorf("Error VarStatement.Value: expected %d, got %+v.", expectedValue[i], varStmt.Value)
+			}
 		}
 	})
 
@@ -91,15 +95,78 @@ func TestParser_ParseStatement(t *testing.T) {
 			t.Errorf("Error statement length for program root: expected %d, got %d.", 3, len(astRoot.Statements))
 		}
 
-		for _, stmt := range astRoot.Statements {
+		expectedValue := []int64{5, 10}
+
+		for i, stmt := range astRoot.Statements {
 			if stmt.TokenLiteral() != "return" {
 				t.Errorf("Error s.TokenLiteral: expected %q, got %q.\n", "return", stmt.TokenLiteral())
 			}
 
-			_, ok := stmt.(*ast.ReturnStatement)
+			returnStmt, ok := stmt.(*ast.ReturnStatement)
 			if !ok {
 				t.Errorf("Error statement type: expected *ast.ReturnStatement, got %T.\n", stmt)
+				continue
 			}
+
+			if !correctIntegerLiteral(t, returnStmt.ReturnValue, expectedValue[i]) {
+				t.Errorf("Error ReturnStatement.ReturnValue: expected %d, got %+v.", expectedValue[i], returnStmt.ReturnValue)
+			}
+		}
+	})
+
+	t.Run("Computed 'Var' and 'Return' values", func(t *testing.T) {
+		input := `var x = 1 + 2;
+			      return 3 * 4;`
+
+		l := lexer.New(input)
+		p := New(l)
+		astRoot := p.ParseProgram()
+		if len(p.errors) != 0 {
+			t.Fatalf("Error parsing input: %v.", p.errors)
+		}
+		if len(astRoot.Statements) != 2 {
+			t.Fatalf("Error statement length for program root: expected %d, got %d.", 2, len(astRoot.Statements))
+		}
+
+		varStmt := astRoot.Statements[0].(*ast.VarStatement)
+		if !correctInfixExpression(t, varStmt.Value, 1, "+", 2) {
+			t.Errorf("Error VarStatement.Value: expected 1 + 2, got %+v.", varStmt.Value)
+		}
+
+		returnStmt := astRoot.Statements[1].(*ast.ReturnStatement)
+		if !correctInfixExpression(t, returnStmt.ReturnValue, 3, "*", 4) {
+			t.Errorf("Error ReturnStatement.ReturnValue: expected 3 * 4, got %+v.", returnStmt.ReturnValue)
+		}
+	})
+
+	t.Run("Errors carry the position of the offending token", func(t *testing.T) {
+		input := `var x = 5;
+			      var y 10;`
+
+		l := lexer.NewFile("main.lisa", input)
+		p := New(l)
+		p.ParseProgram()
+
+		if len(p.errors) != 1 {
+			t.Fatalf("Error length of expected errors, expected %d, got %d: %v.", 1, len(p.errors), p.errors)
+		}
+		expectedErr := "main.lisa:2:16: error next token type: expected TYPE(=), got TYPE(INT)."
+		if p.errors[0].Error() != expectedErr {
+			t.Errorf("Error message: expected %q, got %q.", expectedErr, p.errors[0].Error())
+		}
+	})
+
+	t.Run("Malformed 'Var' value names the offending token", func(t *testing.T) {
+		l := lexer.New("var x = 1 + ;")
+		p := New(l)
+		p.ParseProgram()
+
+		if len(p.errors) != 1 {
+			t.Fatalf("Error length of expected errors, expected %d, got %d: %v.", 1, len(p.errors), p.errors)
+		}
+		expectedErr := `1:13: error unexpected token ";": no prefix parse function for TYPE(;) found.`
+		if p.errors[0].Error() != expectedErr {
+			t.Errorf("Error message: expected %q, got %q.", expectedErr, p.errors[0].Error())
 		}
 	})
 
@@ -190,6 +257,214 @@ func TestParser_ParseExpression(t *testing.T) {
 		}
 	})
 
+	t.Run("Test Expression - Integer Literals in other bases", func(t *testing.T) {
+		testCases := []struct {
+			input    string
+			expected int64
+		}{
+			{"0xFF;", 255},
+			{"0XdeadBEEF;", 0xdeadbeef},
+			{"0o755;", 493},
+			{"0O17;", 15},
+			{"0b1010;", 10},
+			{"0B1;", 1},
+			// A leading '0' without a prefix is still decimal.
+			{"0755;", 755},
+			{"1_000_000;", 1000000},
+			{"0x_7FFF_FFFF_FFFF_FFFF;", 9223372036854775807},
+			{"0b_1111_0000;", 240},
+		}
+
+		for i, tc := range testCases {
+			l := lexer.New(tc.input)
+			p := New(l)
+			astRoot := p.ParseProgram()
+			if len(p.errors) != 0 {
+				t.Fatalf("tests[%d] - error parsing %q: %v.", i, tc.input, p.Errors())
+			}
+
+			lit, ok := astRoot.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.IntegerLiteralExpression)
+			if !ok {
+				t.Fatalf("tests[%d] - error expression type: expected *ast.IntegerLiteralExpression, got %T", i, astRoot.Statements[0].(*ast.ExpressionStatement).Expression)
+			}
+			if lit.Value != tc.expected {
+				t.Errorf("tests[%d] - error IntegerLiteralExpression.Value: expected %d, got %d.", i, tc.expected, lit.Value)
+			}
+			// The literal is kept as it's written.
+			if lit.TokenLiteral()+";" != tc.input {
+				t.Errorf("tests[%d] - error IntegerLiteralExpression.TokenLiteral(): expected %s, got %s.", i, tc.input, lit.TokenLiteral())
+			}
+		}
+
+		// The malformed literals are reported by the lexer.
+		errorCases := []struct {
+			input         string
+			expectedCode  ErrorCode
+			expectedError string
+		}{
+			{"0x;", ErrIllegalToken, `1:1: error illegal token: hexadecimal literal "0x" has no digits.`},
+			{"1__0;", ErrIllegalToken, `1:1: error illegal token: '_' must separate successive digits in number literal "1__0".`},
+			{"var x = 0b12;", ErrIllegalToken, `1:9: error illegal token: invalid digit '2' in binary literal "0b12".`},
+		}
+
+		for i, tc := range errorCases {
+			l := lexer.New(tc.input)
+			p := New(l)
+			p.ParseProgram()
+			if len(p.errors) != 1 {
+				t.Errorf("tests[%d] - error length of expected errors for %q: expected %d, got %d: %v.", i, tc.input, 1, len(p.errors), p.Errors())
+				continue
+			}
+			if p.errors[0].Code != tc.expectedCode {
+				t.Errorf("tests[%d] - error code: expected %s, got %s.", i, tc.expectedCode, p.errors[0].Code)
+			}
+			if p.errors[0].Error() != tc.expectedError {
+				t.Errorf("tests[%d] - error message: expected %q, got %q.", i, tc.expectedError, p.errors[0].Error())
+			}
+		}
+
+		// The malformed literal is replaced with a placeholder, the expression around it is complete.
+		// The statement ends up as an *ast.BadStatement, thus the expression is parsed on its own.
+		l := lexer.New("1 + 0x;")
+		p := New(l)
+		exp := p.parseExpression(LOWEST)
+		if len(p.errors) != 1 {
+			t.Fatalf("Error length of expected errors, expected %d, got %d: %v.", 1, len(p.errors), p.Errors())
+		}
+		infix, ok := exp.(*ast.InfixExpression)
+		if !ok {
+			t.Fatalf("Error expression type: expected *ast.InfixExpression, got %T.", exp)
+		}
+		if _, ok := infix.Right.(*ast.BadExpression); !ok {
+			t.Errorf("Error right operand type: expected *ast.BadExpression, got %T.", infix.Right)
+		}
+	})
+
+	t.Run("Test Expression - Integer Literals out of the range of an int64", func(t *testing.T) {
+		testCases := []struct {
+			input    string
+			expected string
+		}{
+			{"9223372036854775808;", "9223372036854775808"},
+			{"0x8000_0000_0000_0000;", "9223372036854775808"},
+			{"123456789012345678901234567890;", "123456789012345678901234567890"},
+			{"0b1_0000000000000000000000000000000000000000000000000000000000000000;", "18446744073709551616"},
+		}
+
+		for i, tc := range testCases {
+			l := lexer.New(tc.input)
+			p := New(l)
+			astRoot := p.ParseProgram()
+			if len(p.errors) != 0 {
+				t.Fatalf("tests[%d] - error parsing %q: %v.", i, tc.input, p.Errors())
+			}
+
+			lit, ok := astRoot.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.IntegerLiteralExpression)
+			if !ok {
+				t.Fatalf("tests[%d] - error expression type: expected *ast.IntegerLiteralExpression, got %T", i, astRoot.Statements[0].(*ast.ExpressionStatement).Expression)
+			}
+			if lit.Big == nil || lit.Big.String() != tc.expected {
+				t.Errorf("tests[%d] - error IntegerLiteralExpression.Big: expected %s, got %v.", i, tc.expected, lit.Big)
+			}
+			if lit.Value != 0 {
+				t.Errorf("tests[%d] - error IntegerLiteralExpression.Value: expected 0, got %d.", i, lit.Value)
+			}
+		}
+
+		// The largest int64 is still held by Value.
+		l := lexer.New("9223372036854775807;")
+		p := New(l)
+		astRoot := p.ParseProgram()
+		lit := astRoot.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.IntegerLiteralExpression)
+		if lit.Big != nil || lit.Value != 9223372036854775807 {
+			t.Errorf("Error IntegerLiteralExpression: expected Value 9223372036854775807 and no Big, got %d and %v.", lit.Value, lit.Big)
+		}
+	})
+
+	t.Run("Test Expression - Float Literals", func(t *testing.T) {
+		testCases := []struct {
+			input    string
+			expected float64
+		}{
+			{"3.14;", 3.14},
+			{"1.5e-3;", 1.5e-3},
+			{"2E10;", 2e10},
+		}
+
+		for i, tc := range testCases {
+			l := lexer.New(tc.input)
+			p := New(l)
+			astRoot := p.ParseProgram()
+			if len(p.errors) != 0 {
+				t.Fatalf("tests[%d] - error parsing %q: %v.", i, tc.input, p.Errors())
+			}
+
+			lit, ok := astRoot.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FloatLiteral)
+			if !ok {
+				t.Fatalf("tests[%d] - error expression type: expected *ast.FloatLiteral, got %T", i, astRoot.Statements[0].(*ast.ExpressionStatement).Expression)
+			}
+			if lit.Value != tc.expected {
+				t.Errorf("tests[%d] - error FloatLiteral.Value: expected %g, got %g.", i, tc.expected, lit.Value)
+			}
+			if lit.TokenLiteral()+";" != tc.input {
+				t.Errorf("tests[%d] - error FloatLiteral.TokenLiteral(): expected %s, got %s.", i, tc.input, lit.TokenLiteral())
+			}
+		}
+
+		// A float out of the range of a float64 can't be parsed.
+		l := lexer.New("1e400;")
+		p := New(l)
+		p.ParseProgram()
+		if len(p.errors) != 1 || p.errors[0].Code != ErrInvalidFloat {
+			t.Fatalf("Error parsing 1e400: expected an %s error, got %v.", ErrInvalidFloat, p.Errors())
+		}
+		expected := `1:1: error could not parse "1e400" as float`
+		if p.errors[0].Error() != expected {
+			t.Errorf("Error message: expected %q, got %q.", expected, p.errors[0].Error())
+		}
+
+		// A float without digits after the '.' is reported as such, with a single error.
+		l = lexer.New("var x = 1.;")
+		p = New(l)
+		p.ParseProgram()
+		if len(p.errors) != 1 || p.errors[0].Code != ErrIllegalToken {
+			t.Fatalf("Error parsing 1.: expected an %s error, got %v.", ErrIllegalToken, p.Errors())
+		}
+		expected = `1:9: error illegal token: malformed float literal "1.".`
+		if p.errors[0].Error() != expected {
+			t.Errorf("Error message: expected %q, got %q.", expected, p.errors[0].Error())
+		}
+	})
+
+	t.Run("Test Expression - String Literals", func(t *testing.T) {
+		l := lexer.New(`"hello world";`)
+		p := New(l)
+		astRoot := p.ParseProgram()
+		if len(p.errors) != 0 {
+			t.Fatalf("Error parsing input: %v.", p.errors)
+		}
+
+		str, ok := astRoot.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.StringLiteral)
+		if !ok {
+			t.Fatalf("Error expression type: expected *ast.StringLiteral, got %T", astRoot.Statements[0].(*ast.ExpressionStatement).Expression)
+		}
+		if str.Value != "hello world" {
+			t.Errorf("Error StringLiteral.Value: expected %q, got %q.", "hello world", str.Value)
+		}
+
+		// The error of an unterminated string is located at the opening quote.
+		l = lexer.New(`var s = "hello;`)
+		p = New(l)
+		p.ParseProgram()
+		if len(p.errors) != 1 {
+			t.Fatalf("Error length of expected errors, expected %d, got %d: %v.", 1, len(p.errors), p.errors)
+		}
+		expectedErr := "1:9: error illegal token: unterminated string literal."
+		if p.errors[0].Error() != expectedErr {
+			t.Errorf("Error message: expected %q, got %q.", expectedErr, p.errors[0].Error())
+		}
+	})
+
 	t.Run("Test Expression - Prefix Operators", func(t *testing.T) {
 		prefixTestsCases := []struct {
 			input        string
@@ -237,9 +512,551 @@ func TestParser_ParseExpression(t *testing.T) {
 	})
 
 	t.Run("Test Expression - Infix Operators", func(t *testing.T) {
+		infixTestCases := []struct {
+			input      string
+			leftValue  int64
+			operator   string
+			rightValue int64
+		}{
+			{"5 + 6;", 5, "+", 6},
+			{"5 - 6;", 5, "-", 6},
+			{"5 * 6;", 5, "*", 6},
+			{"5 / 6;", 5, "/", 6},
+			{"5 ^ 6;", 5, "^", 6},
+			{"5 > 6;", 5, ">", 6},
+			{"5 < 6;", 5, "<", 6},
+			{"5 == 6;", 5, "==", 6},
+			{"5 != 6;", 5, "!=", 6},
+		}
+
+		for i, itc := range infixTestCases {
+			l := lexer.New(itc.input)
+			p := New(l)
+			astRoot := p.ParseProgram()
+
+			if len(p.errors) != 0 {
+				t.Errorf("tests[%d] - error parsing %q: %v.", i, itc.input, p.errors)
+			}
+			if len(astRoot.Statements) != 1 {
+				t.Fatalf("tests[%d] - error statement length for program root: expected %d, got %d.", i, 1, len(astRoot.Statements))
+			}
 
+			stmt, ok := astRoot.Statements[0].(*ast.ExpressionStatement)
+			if !ok {
+				t.Fatalf("tests[%d] - error statement type: expected *ast.ExpressionStatement, got %T", i, astRoot.Statements[0])
+			}
+			if !correctInfixExpression(t, stmt.Expression, itc.leftValue, itc.operator, itc.rightValue) {
+				t.Errorf("tests[%d] - error infix expression: expected %d %s %d, got %+v.", i, itc.leftValue, itc.operator, itc.rightValue, stmt.Expression)
+			}
+		}
+	})
+
+	t.Run("Test Expression - Operator Precedence", func(t *testing.T) {
+		// '5 + 6 * 2' should be parsed as '5 + (6 * 2)'.
+		l := lexer.New("5 + 6 * 2;")
+		p := New(l)
+		astRoot := p.ParseProgram()
+		if len(p.errors) != 0 {
+			t.Fatalf("Error parsing input: %v.", p.errors)
+		}
+
+		exp, ok := astRoot.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.InfixExpression)
+		if !ok || exp.Operator != "+" || !correctIntegerLiteral(t, exp.Left, 5) {
+			t.Fatalf("Error infix expression: expected 5 + (6 * 2), got %+v.", exp)
+		}
+		if !correctInfixExpression(t, exp.Right, 6, "*", 2) {
+			t.Errorf("Error right side of infix expression: expected 6 * 2, got %+v.", exp.Right)
+		}
+
+		// '2 ^ 3 ^ 2' should be parsed as '2 ^ (3 ^ 2)'.
+		l = lexer.New("2 ^ 3 ^ 2;")
+		p = New(l)
+		astRoot = p.ParseProgram()
+		if len(p.errors) != 0 {
+			t.Fatalf("Error parsing input: %v.", p.errors)
+		}
+
+		exp, ok = astRoot.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.InfixExpression)
+		if !ok || exp.Operator != "^" || !correctIntegerLiteral(t, exp.Left, 2) {
+			t.Fatalf("Error infix expression: expected 2 ^ (3 ^ 2), got %+v.", exp)
+		}
+		if !correctInfixExpression(t, exp.Right, 3, "^", 2) {
+			t.Errorf("Error right side of infix expression: expected 3 ^ 2, got %+v.", exp.Right)
+		}
+
+		// '(5 + 6) * 2' should be parsed as '(5 + 6) * 2'.
+		l = lexer.New("(5 + 6) * 2;")
+		p = New(l)
+		astRoot = p.ParseProgram()
+		if len(p.errors) != 0 {
+			t.Fatalf("Error parsing input: %v.", p.errors)
+		}
+
+		exp, ok = astRoot.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.InfixExpression)
+		if !ok || exp.Operator != "*" || !correctIntegerLiteral(t, exp.Right, 2) {
+			t.Fatalf("Error infix expression: expected (5 + 6) * 2, got %+v.", exp)
+		}
+		if !correctInfixExpression(t, exp.Left, 5, "+", 6) {
+			t.Errorf("Error left side of infix expression: expected 5 + 6, got %+v.", exp.Left)
+		}
 	})
 
+	t.Run("Test Expression - Operator Precedence (golden)", func(t *testing.T) {
+		testCases := []struct {
+			input    string
+			expected string
+		}{
+			{"-a * b;", "((-a) * b);"},
+			{"!-a;", "(!(-a));"},
+			{"a + b + c;", "((a + b) + c);"},
+			{"a + b - c;", "((a + b) - c);"},
+			{"a * b * c;", "((a * b) * c);"},
+			{"a * b / c;", "((a * b) / c);"},
+			{"a + b / c;", "(a + (b / c));"},
+			{"a + b * c + d / e - f;", "(((a + (b * c)) + (d / e)) - f);"},
+			{"3 + 4; -5 * 5;", "(3 + 4);\n((-5) * 5);"},
+			{"5 > 4 == 3 < 4;", "((5 > 4) == (3 < 4));"},
+			{"5 < 4 != 3 > 4;", "((5 < 4) != (3 > 4));"},
+			{"3 + 4 * 5 == 3 * 1 + 4 * 5;", "((3 + (4 * 5)) == ((3 * 1) + (4 * 5)));"},
+			{"true == !false;", "(true == (!false));"},
+			{"1 + (2 + 3) + 4;", "((1 + (2 + 3)) + 4);"},
+			{"-(5 + 5);", "(-(5 + 5));"},
+			{"2 ^ 3 ^ 2;", "(2 ^ (3 ^ 2));"},
+			{"-2 ^ 2;", "(-(2 ^ 2));"},
+			{"2 * 3 ^ 2;", "(2 * (3 ^ 2));"},
+			{"a + add(b * c) + d;", "((a + add((b * c))) + d);"},
+			{"add(a, b, 1, 2 * 3, 4 + 5, add(6, 7 * 8));", "add(a, b, 1, (2 * 3), (4 + 5), add(6, (7 * 8)));"},
+			{"var x = if (a < b) { a; } else { b; };", "var x = if ((a < b)) { a; } else { b; };"},
+			{"return fn(x, y) { x + y; };", "return fn(x, y) { (x + y); };"},
+			{`"a\n\"b\"" + "c";`, `("a\n\"b\"" + "c");`},
+			{"a * [1, 2, 3, 4][b * c] * d;", "((a * ([1, 2, 3, 4][(b * c)])) * d);"},
+			{"add(a * b[2], b[1], 2 * [1, 2][1]);", "add((a * (b[2])), (b[1]), (2 * ([1, 2][1])));"},
+			{"-a[0];", "(-(a[0]));"},
+			{"a[0] ^ 2;", "((a[0]) ^ 2);"},
+			{"f(x)[0];", "(f(x)[0]);"},
+			{"a[0](x);", "(a[0])(x);"},
+			{"a[1][2];", "((a[1])[2]);"},
+			{"a[1:2][0];", "((a[1:2])[0]);"},
+			{"a[:];", "(a[:]);"},
+			{"a[-1:];", "(a[(-1):]);"},
+			{"a[:b + 1];", "(a[:(b + 1)]);"},
+			{"-1.5 * 2e3 ^ 0.5;", "((-1.5) * (2e3 ^ 0.5));"},
+			{`{"a": 1 + 2, b: c * d, 3: [4]};`, `{"a": (1 + 2), b: (c * d), 3: [4]};`},
+			{`h["a"][0];`, `((h["a"])[0]);`},
+			{"var f = fn() { {}; };", "var f = fn() { {}; };"},
+		}
+
+		for i, tc := range testCases {
+			l := lexer.New(tc.input)
+			p := New(l)
+			astRoot := p.ParseProgram()
+			if len(p.errors) != 0 {
+				t.Errorf("tests[%d] - error parsing %q: %v.", i, tc.input, p.Errors())
+				continue
+			}
+			if astRoot.String() != tc.expected {
+				t.Errorf("tests[%d] - error String(): expected %q, got %q.", i, tc.expected, astRoot.String())
+			}
+		}
+	})
+
+	t.Run("Test Expression - If Expressions", func(t *testing.T) {
+		input := `if (x < y) { x; }
+			      var z = if (x > y) { x; } else { y; };`
+
+		l := lexer.New(input)
+		p := New(l)
+		astRoot := p.ParseProgram()
+		if len(p.errors) != 0 {
+			t.Fatalf("Error parsing input: %v.", p.errors)
+		}
+		if len(astRoot.Statements) != 2 {
+			t.Fatalf("Error statement length for program root: expected %d, got %d.", 2, len(astRoot.Statements))
+		}
+
+		// if (x < y) { x; }
+		stmt, ok := astRoot.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("Error statement type: expected *ast.ExpressionStatement, got %T", astRoot.Statements[0])
+		}
+		exp, ok := stmt.Expression.(*ast.IfExpression)
+		if !ok {
+			t.Fatalf("Error expression type: expected *ast.IfExpression, got %T", stmt.Expression)
+		}
+		condition, ok := exp.Condition.(*ast.InfixExpression)
+		if !ok || condition.Operator != "<" || !correctIdentifier(t, condition.Left, "x") || !correctIdentifier(t, condition.Right, "y") {
+			t.Errorf("Error IfExpression.Condition: expected x < y, got %+v.", exp.Condition)
+		}
+		if len(exp.Consequence.Statements) != 1 {
+			t.Fatalf("Error consequence length: expected %d, got %d.", 1, len(exp.Consequence.Statements))
+		}
+		consequence, ok := exp.Consequence.Statements[0].(*ast.ExpressionStatement)
+		if !ok || !correctIdentifier(t, consequence.Expression, "x") {
+			t.Errorf("Error IfExpression.Consequence: expected x, got %+v.", exp.Consequence.Statements[0])
+		}
+		if exp.Alternative != nil {
+			t.Errorf("Error IfExpression.Alternative: expected nil, got %+v.", exp.Alternative)
+		}
+
+		// var z = if (x > y) { x; } else { y; };
+		varStmt, ok := astRoot.Statements[1].(*ast.VarStatement)
+		if !ok {
+			t.Fatalf("Error statement type: expected *ast.VarStatement, got %T", astRoot.Statements[1])
+		}
+		exp, ok = varStmt.Value.(*ast.IfExpression)
+		if !ok {
+			t.Fatalf("Error expression type: expected *ast.IfExpression, got %T", varStmt.Value)
+		}
+		if exp.Alternative == nil || len(exp.Alternative.Statements) != 1 {
+			t.Fatalf("Error IfExpression.Alternative: expected one statement, got %+v.", exp.Alternative)
+		}
+		alternative, ok := exp.Alternative.Statements[0].(*ast.ExpressionStatement)
+		if !ok || !correctIdentifier(t, alternative.Expression, "y") {
+			t.Errorf("Error IfExpression.Alternative: expected y, got %+v.", exp.Alternative.Statements[0])
+		}
+	})
+
+	t.Run("Test Expression - If Statements", func(t *testing.T) {
+		// A statement that starts with 'if' ends at the '}', the next line doesn't continue the if expression.
+		testCases := []struct {
+			input    string
+			expected []string
+		}{
+			{"if (c) { 1; }\n-1;", []string{"if (c) { 1; };", "(-1);"}},
+			{"var x = 5; if (x > 1) { x; }\n(3);", []string{"var x = 5;", "if ((x > 1)) { x; };", "3;"}},
+			{"if (c) { 1; }\n[1, 2];", []string{"if (c) { 1; };", "[1, 2];"}},
+			// The ';' after it is optional.
+			{"if (c) { 1; } else { 2; }; -1;", []string{"if (c) { 1; } else { 2; };", "(-1);"}},
+		}
+
+		for i, tc := range testCases {
+			l := lexer.New(tc.input)
+			p := New(l)
+			astRoot := p.ParseProgram()
+			if len(p.errors) != 0 {
+				t.Errorf("tests[%d] - error parsing %q: %v.", i, tc.input, p.Errors())
+				continue
+			}
+			if len(astRoot.Statements) != len(tc.expected) {
+				t.Errorf("tests[%d] - error statement length for %q: expected %d, got %d.", i, tc.input, len(tc.expected), len(astRoot.Statements))
+				continue
+			}
+			for j, stmt := range astRoot.Statements {
+				if stmt.String() != tc.expected[j] {
+					t.Errorf("tests[%d] - error statement[%d]: expected %q, got %q.", i, j, tc.expected[j], stmt.String())
+				}
+			}
+		}
+	})
+
+	t.Run("Test Expression - Incorrect If Expressions", func(t *testing.T) {
+		inputs := []string{
+			"if x { x; }",
+			"if (x) x;",
+			"if (x) { x; } else x;",
+			"if (x) { x;",
+		}
+
+		for i, input := range inputs {
+			l := lexer.New(input)
+			p := New(l)
+			p.ParseProgram()
+			if len(p.errors) == 0 {
+				t.Errorf("tests[%d] - error parsing %q: expected errors, got none.", i, input)
+			}
+		}
+	})
+
+	t.Run("Test Expression - Function Literals", func(t *testing.T) {
+		paramTestCases := []struct {
+			input          string
+			expectedParams []string
+		}{
+			{"fn() {};", []string{}},
+			{"fn(x) {};", []string{"x"}},
+			{"fn(x, y, z) { return x + y; };", []string{"x", "y", "z"}},
+		}
+
+		for i, ptc := range paramTestCases {
+			l := lexer.New(ptc.input)
+			p := New(l)
+			astRoot := p.ParseProgram()
+			if len(p.errors) != 0 {
+				t.Fatalf("tests[%d] - error parsing %q: %v.", i, ptc.input, p.errors)
+			}
+
+			fn, ok := astRoot.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+			if !ok {
+				t.Fatalf("tests[%d] - error expression type: expected *ast.FunctionLiteral, got %T", i, astRoot.Statements[0].(*ast.ExpressionStatement).Expression)
+			}
+			if len(fn.Parameters) != len(ptc.expectedParams) {
+				t.Fatalf("tests[%d] - error parameter length: expected %d, got %d.", i, len(ptc.expectedParams), len(fn.Parameters))
+			}
+			for j, param := range ptc.expectedParams {
+				if !correctIdentifier(t, fn.Parameters[j], param) {
+					t.Errorf("tests[%d] - error parameter[%d]: expected %s, got %+v.", i, j, param, fn.Parameters[j])
+				}
+			}
+		}
+
+		l := lexer.New("fn(x, y) { return x + y; };")
+		p := New(l)
+		astRoot := p.ParseProgram()
+		fn := astRoot.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+		if len(fn.Body.Statements) != 1 {
+			t.Fatalf("Error function body length: expected %d, got %d.", 1, len(fn.Body.Statements))
+		}
+		if _, ok := fn.Body.Statements[0].(*ast.ReturnStatement); !ok {
+			t.Errorf("Error function body statement type: expected *ast.ReturnStatement, got %T.", fn.Body.Statements[0])
+		}
+	})
+
+	t.Run("Test Expression - Call Expressions", func(t *testing.T) {
+		l := lexer.New("add(1, 2 * 3, 4 + 5);")
+		p := New(l)
+		astRoot := p.ParseProgram()
+		if len(p.errors) != 0 {
+			t.Fatalf("Error parsing input: %v.", p.errors)
+		}
+
+		exp, ok := astRoot.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.CallExpression)
+		if !ok {
+			t.Fatalf("Error expression type: expected *ast.CallExpression, got %T", astRoot.Statements[0].(*ast.ExpressionStatement).Expression)
+		}
+		if !correctIdentifier(t, exp.Function, "add") {
+			t.Errorf("Error CallExpression.Function: expected add, got %+v.", exp.Function)
+		}
+		if len(exp.Arguments) != 3 {
+			t.Fatalf("Error argument length: expected %d, got %d.", 3, len(exp.Arguments))
+		}
+		if !correctIntegerLiteral(t, exp.Arguments[0], 1) {
+			t.Errorf("Error argument[0]: expected 1, got %+v.", exp.Arguments[0])
+		}
+		if !correctInfixExpression(t, exp.Arguments[1], 2, "*", 3) {
+			t.Errorf("Error argument[1]: expected 2 * 3, got %+v.", exp.Arguments[1])
+		}
+		if !correctInfixExpression(t, exp.Arguments[2], 4, "+", 5) {
+			t.Errorf("Error argument[2]: expected 4 + 5, got %+v.", exp.Arguments[2])
+		}
+
+		// The position of a call is the position of the called function.
+		if exp.Pos().Line != 1 || exp.Pos().Column != 1 {
+			t.Errorf("Error CallExpression.Pos: expected 1:1, got %s.", exp.Pos())
+		}
+
+		// A call binds tighter than any operator, 'a + add(b) * c' is 'a + (add(b) * c)'.
+		l = lexer.New("a + add(b) * c;")
+		p = New(l)
+		astRoot = p.ParseProgram()
+		infix := astRoot.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.InfixExpression)
+		right, ok := infix.Right.(*ast.InfixExpression)
+		if !ok || right.Operator != "*" {
+			t.Fatalf("Error infix expression: expected a + (add(b) * c), got %+v.", infix)
+		}
+		if _, ok := right.Left.(*ast.CallExpression); !ok {
+			t.Errorf("Error infix expression: expected a call on the left of '*', got %T.", right.Left)
+		}
+	})
+
+	t.Run("Test Expression - Array Literals", func(t *testing.T) {
+		l := lexer.New("[1, 2 * 2, 3 + 3];")
+		p := New(l)
+		astRoot := p.ParseProgram()
+		if len(p.errors) != 0 {
+			t.Fatalf("Error parsing input: %v.", p.errors)
+		}
+
+		array, ok := astRoot.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.ArrayLiteral)
+		if !ok {
+			t.Fatalf("Error expression type: expected *ast.ArrayLiteral, got %T", astRoot.Statements[0].(*ast.ExpressionStatement).Expression)
+		}
+		if len(array.Elements) != 3 {
+			t.Fatalf("Error element length: expected %d, got %d.", 3, len(array.Elements))
+		}
+		if !correctIntegerLiteral(t, array.Elements[0], 1) {
+			t.Errorf("Error element[0]: expected 1, got %+v.", array.Elements[0])
+		}
+		if !correctInfixExpression(t, array.Elements[1], 2, "*", 2) {
+			t.Errorf("Error element[1]: expected 2 * 2, got %+v.", array.Elements[1])
+		}
+		if !correctInfixExpression(t, array.Elements[2], 3, "+", 3) {
+			t.Errorf("Error element[2]: expected 3 + 3, got %+v.", array.Elements[2])
+		}
+
+		l = lexer.New("[];")
+		p = New(l)
+		astRoot = p.ParseProgram()
+		array, ok = astRoot.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.ArrayLiteral)
+		if !ok || len(array.Elements) != 0 {
+			t.Errorf("Error empty array literal: expected [], got %+v.", astRoot.Statements[0])
+		}
+	})
+
+	t.Run("Test Expression - Index Expressions", func(t *testing.T) {
+		l := lexer.New("myArray[1 + 1];")
+		p := New(l)
+		astRoot := p.ParseProgram()
+		if len(p.errors) != 0 {
+			t.Fatalf("Error parsing input: %v.", p.errors)
+		}
+
+		exp, ok := astRoot.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.IndexExpression)
+		if !ok {
+			t.Fatalf("Error expression type: expected *ast.IndexExpression, got %T", astRoot.Statements[0].(*ast.ExpressionStatement).Expression)
+		}
+		if !correctIdentifier(t, exp.Left, "myArray") {
+			t.Errorf("Error IndexExpression.Left: expected myArray, got %+v.", exp.Left)
+		}
+		if !correctInfixExpression(t, exp.Index, 1, "+", 1) {
+			t.Errorf("Error IndexExpression.Index: expected 1 + 1, got %+v.", exp.Index)
+		}
+
+		// The position of an index expression is the position of the indexed expression.
+		if exp.Pos().Line != 1 || exp.Pos().Column != 1 {
+			t.Errorf("Error IndexExpression.Pos: expected 1:1, got %s.", exp.Pos())
+		}
+	})
+
+	t.Run("Test Expression - Slice Expressions", func(t *testing.T) {
+		testCases := []struct {
+			input        string
+			expectedLow  any
+			expectedHigh any
+		}{
+			{"arr[1:3];", int64(1), int64(3)},
+			{"arr[1:];", int64(1), nil},
+			{"arr[:3];", nil, int64(3)},
+			{"arr[:];", nil, nil},
+		}
+
+		for i, tc := range testCases {
+			l := lexer.New(tc.input)
+			p := New(l)
+			astRoot := p.ParseProgram()
+			if len(p.errors) != 0 {
+				t.Fatalf("tests[%d] - error parsing %q: %v.", i, tc.input, p.Errors())
+			}
+
+			exp, ok := astRoot.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.SliceExpression)
+			if !ok {
+				t.Fatalf("tests[%d] - error expression type: expected *ast.SliceExpression, got %T", i, astRoot.Statements[0].(*ast.ExpressionStatement).Expression)
+			}
+			if !correctIdentifier(t, exp.Left, "arr") {
+				t.Errorf("tests[%d] - error SliceExpression.Left: expected arr, got %+v.", i, exp.Left)
+			}
+			for _, bound := range []struct {
+				name     string
+				exp      ast.Expression
+				expected any
+			}{{"Low", exp.Low, tc.expectedLow}, {"High", exp.High, tc.expectedHigh}} {
+				if bound.expected == nil {
+					if bound.exp != nil {
+						t.Errorf("tests[%d] - error SliceExpression.%s: expected nil, got %+v.", i, bound.name, bound.exp)
+					}
+				} else if !correctIntegerLiteral(t, bound.exp, bound.expected.(int64)) {
+					t.Errorf("tests[%d] - error SliceExpression.%s: expected %d, got %+v.", i, bound.name, bound.expected, bound.exp)
+				}
+			}
+		}
+	})
+
+	t.Run("Test Expression - Hash Literals", func(t *testing.T) {
+		l := lexer.New(`{"one": 1, "two": 2 * 2, 3: true};`)
+		p := New(l)
+		astRoot := p.ParseProgram()
+		if len(p.errors) != 0 {
+			t.Fatalf("Error parsing input: %v.", p.errors)
+		}
+
+		hash, ok := astRoot.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.HashLiteral)
+		if !ok {
+			t.Fatalf("Error expression type: expected *ast.HashLiteral, got %T", astRoot.Statements[0].(*ast.ExpressionStatement).Expression)
+		}
+		if len(hash.Pairs) != 3 {
+			t.Fatalf("Error pair length: expected %d, got %d.", 3, len(hash.Pairs))
+		}
+
+		// The pairs keep the order they're written in.
+		expectedKeys := []string{`"one"`, `"two"`, "3"}
+		for i, key := range expectedKeys {
+			if hash.Pairs[i].Key.String() != key {
+				t.Errorf("tests[%d] - error key: expected %s, got %s.", i, key, hash.Pairs[i].Key.String())
+			}
+		}
+		if !correctIntegerLiteral(t, hash.Pairs[0].Value, 1) {
+			t.Errorf("Error value of \"one\": expected 1, got %+v.", hash.Pairs[0].Value)
+		}
+		if !correctInfixExpression(t, hash.Pairs[1].Value, 2, "*", 2) {
+			t.Errorf("Error value of \"two\": expected 2 * 2, got %+v.", hash.Pairs[1].Value)
+		}
+		if b, ok := hash.Pairs[2].Value.(*ast.BooleanExpression); !ok || !b.Value {
+			t.Errorf("Error value of 3: expected true, got %+v.", hash.Pairs[2].Value)
+		}
+
+		l = lexer.New("{};")
+		p = New(l)
+		astRoot = p.ParseProgram()
+		hash, ok = astRoot.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.HashLiteral)
+		if !ok || len(hash.Pairs) != 0 {
+			t.Errorf("Error empty hash literal: expected {}, got %+v.", astRoot.Statements[0])
+		}
+
+		// The block of an if expression isn't a hash literal, even if it's empty.
+		l = lexer.New("if (x) {} else { {}; }")
+		p = New(l)
+		astRoot = p.ParseProgram()
+		if len(p.errors) != 0 {
+			t.Fatalf("Error parsing input: %v.", p.errors)
+		}
+		ifExp := astRoot.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.IfExpression)
+		if len(ifExp.Consequence.Statements) != 0 {
+			t.Errorf("Error consequence length: expected %d, got %d.", 0, len(ifExp.Consequence.Statements))
+		}
+		if len(ifExp.Alternative.Statements) != 1 {
+			t.Fatalf("Error alternative length: expected %d, got %d.", 1, len(ifExp.Alternative.Statements))
+		}
+		if _, ok := ifExp.Alternative.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.HashLiteral); !ok {
+			t.Errorf("Error alternative statement: expected a hash literal, got %+v.", ifExp.Alternative.Statements[0])
+		}
+	})
+
+	t.Run("Test Expression - Incorrect Arrays, Hashes, Index and Slice Expressions", func(t *testing.T) {
+		testCases := []struct {
+			input         string
+			expectedError string
+		}{
+			{"[1, 2;", "1:6: error next token type: expected TYPE(]), got TYPE(;)."},
+			{"arr[1;", "1:6: error next token type: expected TYPE(]), got TYPE(;)."},
+			{"arr[1:2;", "1:8: error next token type: expected TYPE(]), got TYPE(;)."},
+			{"arr[];", `1:5: error unexpected token "]": no prefix parse function for TYPE(]) found.`},
+			{"arr[1:2:3];", "1:8: error next token type: expected TYPE(]), got TYPE(:)."},
+			{`{"a" 1};`, "1:6: error next token type: expected TYPE(:), got TYPE(INT)."},
+			{`{"a": 1;`, "1:8: error next token type: expected TYPE(}), got TYPE(;)."},
+			{`{"a": 1,};`, `1:9: error unexpected token "}": no prefix parse function for TYPE(}) found.`},
+			{"{ x; };", "1:4: error next token type: expected TYPE(:), got TYPE(;)."},
+		}
+
+		for i, tc := range testCases {
+			l := lexer.New(tc.input)
+			p := New(l)
+			p.ParseProgram()
+			if len(p.errors) != 1 {
+				t.Errorf("tests[%d] - error length of expected errors for %q: expected %d, got %d: %v.", i, tc.input, 1, len(p.errors), p.Errors())
+				continue
+			}
+			if p.errors[0].Error() != tc.expectedError {
+				t.Errorf("tests[%d] - error message: expected %q, got %q.", i, tc.expectedError, p.errors[0].Error())
+			}
+		}
+	})
+}
+
+func correctIdentifier(t *testing.T, exp ast.Expression, value string) bool {
+	ident, ok := exp.(*ast.IdentifierExpression)
+	if !ok {
+		return false
+	}
+	return ident.Value == value && ident.TokenLiteral() == value
 }
 
 func correctIntegerLiteral(t *testing.T, integerLiteral ast.Expression, value int64) bool {
@@ -261,3 +1078,178 @@ func correctIntegerLiteral(t *testing.T, integerLiteral ast.Expression, value in
 		return true
 	}
 }
+
+func correctInfixExpression(t *testing.T, exp ast.Expression, left int64, operator string, right int64) bool {
+	infix, ok := exp.(*ast.InfixExpression)
+	if !ok {
+		return false
+	}
+	if infix.Operator != operator {
+		return false
+	}
+	return correctIntegerLiteral(t, infix.Left, left) && correctIntegerLiteral(t, infix.Right, right)
+}
+
+func TestParser_ErrorRecovery(t *testing.T) {
+	t.Run("Independent mistakes yield one error each", func(t *testing.T) {
+		input := `var x = 5;
+			      var = 10;
+			      var y = x + ;
+			      return ) 3;
+			      var z = x * 2;`
+
+		l := lexer.New(input)
+		p := New(l)
+		astRoot := p.ParseProgram()
+
+		if len(p.errors) != 3 {
+			t.Fatalf("Error length of expected errors, expected %d, got %d: %v.", 3, len(p.errors), p.Errors())
+		}
+		expectedLines := []int{2, 3, 4}
+		for i, e := range p.errors {
+			if e.Pos.Line != expectedLines[i] {
+				t.Errorf("tests[%d] - error line: expected %d, got %d (%s).", i, expectedLines[i], e.Pos.Line, e)
+			}
+		}
+
+		// The valid statements are kept, the broken ones are replaced by placeholders.
+		expectedTypes := []string{"*ast.VarStatement", "*ast.BadStatement", "*ast.BadStatement", "*ast.BadStatement", "*ast.VarStatement"}
+		if len(astRoot.Statements) != len(expectedTypes) {
+			t.Fatalf("Error statement length for program root: expected %d, got %d.", len(expectedTypes), len(astRoot.Statements))
+		}
+		for i, stmt := range astRoot.Statements {
+			if fmt.Sprintf("%T", stmt) != expectedTypes[i] {
+				t.Errorf("tests[%d] - error statement type: expected %s, got %T.", i, expectedTypes[i], stmt)
+			}
+		}
+
+		lastStmt := astRoot.Statements[4].(*ast.VarStatement)
+		if !correctIdentifier(t, lastStmt.Name, "z") {
+			t.Errorf("Error VarStatement.Name: expected z, got %+v.", lastStmt.Name)
+		}
+	})
+
+	t.Run("Mistakes inside a block are recovered inside the block", func(t *testing.T) {
+		input := `var f = fn(a) { var = 1; return a; };
+			      f(2);`
+
+		l := lexer.New(input)
+		p := New(l)
+		astRoot := p.ParseProgram()
+
+		if len(p.errors) != 1 {
+			t.Fatalf("Error length of expected errors, expected %d, got %d: %v.", 1, len(p.errors), p.Errors())
+		}
+		if len(astRoot.Statements) != 2 {
+			t.Fatalf("Error statement length for program root: expected %d, got %d.", 2, len(astRoot.Statements))
+		}
+
+		varStmt, ok := astRoot.Statements[0].(*ast.VarStatement)
+		if !ok {
+			t.Fatalf("Error statement type: expected *ast.VarStatement, got %T.", astRoot.Statements[0])
+		}
+		fn := varStmt.Value.(*ast.FunctionLiteral)
+		if len(fn.Body.Statements) != 2 {
+			t.Fatalf("Error function body length: expected %d, got %d.", 2, len(fn.Body.Statements))
+		}
+		if _, ok := fn.Body.Statements[0].(*ast.BadStatement); !ok {
+			t.Errorf("Error statement type: expected *ast.BadStatement, got %T.", fn.Body.Statements[0])
+		}
+		if _, ok := fn.Body.Statements[1].(*ast.ReturnStatement); !ok {
+			t.Errorf("Error statement type: expected *ast.ReturnStatement, got %T.", fn.Body.Statements[1])
+		}
+	})
+
+	t.Run("Synchronization points", func(t *testing.T) {
+		testCases := []struct {
+			input string
+			// expectedLast is the type of the statement after the broken one.
+			expectedLast string
+		}{
+			// A broken block is skipped as a whole.
+			{"if x { x; }\nvar ok = 1;", "*ast.VarStatement"},
+			{"var a = (1 + 2;\nvar b = 3;", "*ast.VarStatement"},
+			{"}; var a = 1;", "*ast.VarStatement"},
+			{"add(1, 2; return 3;", "*ast.ReturnStatement"},
+			{"fn(x { x; }; var q = 1;", "*ast.VarStatement"},
+			// The braces of a broken hash literal are skipped as well, even if they were opened before the error.
+			{`{"a" 1, "b": 2}; var q = 1;`, "*ast.VarStatement"},
+			{`var h = {"a": 1 2}; return 3;`, "*ast.ReturnStatement"},
+			{"{x}; var q = 1;", "*ast.VarStatement"},
+			// A missing ';' stops at the next statement keyword.
+			{"var a = 1\nvar b = 2;", "*ast.VarStatement"},
+			{"return 1\nif (x) { x; }", "*ast.ExpressionStatement"},
+		}
+
+		for i, tc := range testCases {
+			l := lexer.New(tc.input)
+			p := New(l)
+			astRoot := p.ParseProgram()
+
+			if len(p.errors) != 1 {
+				t.Errorf("tests[%d] - error length of expected errors, expected %d, got %d: %v.", i, 1, len(p.errors), p.Errors())
+			}
+			if len(astRoot.Statements) != 2 {
+				t.Errorf("tests[%d] - error statement length for program root: expected %d, got %d.", i, 2, len(astRoot.Statements))
+				continue
+			}
+			if _, ok := astRoot.Statements[0].(*ast.BadStatement); !ok {
+				t.Errorf("tests[%d] - error statement type: expected *ast.BadStatement, got %T.", i, astRoot.Statements[0])
+			}
+			if fmt.Sprintf("%T", astRoot.Statements[1]) != tc.expectedLast {
+				t.Errorf("tests[%d] - error statement type: expected %s, got %T.", i, tc.expectedLast, astRoot.Statements[1])
+			}
+		}
+	})
+}
+
+func TestParser_Comments(t *testing.T) {
+	input := `// Leading.
+var f = fn(x) { // After '{'.
+	/* Inside. */
+	x;
+};
+// At the end.`
+
+	expected := []string{"// Leading.", "// After '{'.", "/* Inside. */", "// At the end."}
+
+	// Comments emitted as tokens are collected the same way as the ones attached to tokens.
+	for j, l := range []*lexer.Lexer{lexer.New(input), lexer.New(input, lexer.EmitComments())} {
+		p := New(l)
+		astRoot := p.ParseProgram()
+		if len(p.errors) != 0 {
+			t.Fatalf("lexer[%d] - error parsing: expected no errors, got %v.", j, p.Errors())
+		}
+
+		if len(astRoot.Comments) != len(expected) {
+			t.Fatalf("lexer[%d] - error length of comments: expected %d, got %d.", j, len(expected), len(astRoot.Comments))
+		}
+		for i, c := range astRoot.Comments {
+			if c.Text != expected[i] {
+				t.Errorf("lexer[%d], tests[%d] - error comment text: expected %q, got %q.", j, i, expected[i], c.Text)
+			}
+		}
+	}
+
+	l := lexer.New(input)
+	p := New(l)
+	astRoot := p.ParseProgram()
+
+	// Comments don't change the tree.
+	if astRoot.String() != "var f = fn(x) { x; };" {
+		t.Errorf("error program string: expected %q, got %q.", "var f = fn(x) { x; };", astRoot.String())
+	}
+
+	body := astRoot.Statements[0].(*ast.VarStatement).Value.(*ast.FunctionLiteral).Body
+	if body.Rbrace.Line != 5 || body.Rbrace.Column != 1 {
+		t.Errorf("error position of '}': expected 5:1, got %s.", body.Rbrace)
+	}
+
+	// An unterminated block comment is reported at its opening '/*'.
+	p = New(lexer.New("var x = 1;\n/* never closed\nvar y = 2;"))
+	p.ParseProgram()
+	expectedErr := "2:1: error illegal token: unterminated block comment."
+	if len(p.Errors()) != 1 || p.Errors()[0] != expectedErr {
+		t.Errorf("error unterminated block comment: expected [%s], got %v.", expectedErr, p.Errors())
+	}
+}
@@ -0,0 +1,90 @@
+package repl
+
+import (
+	"Lisa/evaluator"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// metaCommand is a command of the REPL itself, e.g. ':env', args are the words after the name of the command.
+type metaCommand struct {
+	usage string
+	help  string
+	run   func(s *session, args []string)
+}
+
+// metaCommands are the meta-commands by name, including the ':'.
+var metaCommands map[string]metaCommand
+
+func init() {
+	// Assigned in init since ':help' refers to metaCommands.
+	metaCommands = map[string]metaCommand{
+		":reset": {":reset", "remove all the bindings", (*session).reset},
+		":env":   {":env", "list the bindings", (*session).printEnv},
+		":load":  {":load file.lisa", "evaluate a file, its bindings are kept", (*session).load},
+		":help":  {":help", "list the meta-commands", (*session).printHelp},
+	}
+}
+
+// runMetaCommand runs the meta-command on line, which starts with ':'.
+func (s *session) runMetaCommand(line string) {
+	fields := strings.Fields(line)
+	cmd, ok := metaCommands[fields[0]]
+	if !ok {
+		fmt.Fprintf(s.out, "unknown command %s, see :help\n", fields[0])
+		return
+	}
+	cmd.run(s, fields[1:])
+}
+
+// reset implements ':reset', which starts over with an empty environment.
+func (s *session) reset(args []string) {
+	if len(args) != 0 {
+		fmt.Fprintf(s.out, "usage: %s\n", metaCommands[":reset"].usage)
+		return
+	}
+	s.env = evaluator.NewEnvironment()
+}
+
+// printEnv implements ':env', which prints the bindings of the environment sorted by name, one per line.
+func (s *session) printEnv(args []string) {
+	if len(args) != 0 {
+		fmt.Fprintf(s.out, "usage: %s\n", metaCommands[":env"].usage)
+		return
+	}
+	for _, name := range s.env.Names() {
+		val, _ := s.env.Get(name)
+		fmt.Fprintf(s.out, "%s = %s\n", name, val.Inspect())
+	}
+}
+
+// load implements ':load file.lisa', which evaluates a file in the environment of the session, as if it's typed in.
+func (s *session) load(args []string) {
+	if len(args) != 1 {
+		fmt.Fprintf(s.out, "usage: %s\n", metaCommands[":load"].usage)
+		return
+	}
+	src, err := os.ReadFile(args[0])
+	if err != nil {
+		fmt.Fprintf(s.out, "ERROR: %v\n", err)
+		return
+	}
+	s.eval(args[0], string(src))
+}
+
+// printHelp implements ':help', which lists the meta-commands.
+func (s *session) printHelp(args []string) {
+	names := make([]string, 0, len(metaCommands))
+	for name := range metaCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		cmd := metaCommands[name]
+		fmt.Fprintf(s.out, "%-18s%s\n", cmd.usage, cmd.help)
+	}
+	fmt.Fprintf(s.out, "%-18s%s\n", s.cfg.QuitCommand, "end the session")
+}
@@ -0,0 +1,66 @@
+package repl
+
+import (
+	token "Lisa/lexToken"
+	"Lisa/lexer"
+)
+
+// continuingTokens are the tokens that can't end a program, the input goes on after them.
+var continuingTokens = map[token.LexicalType]struct{}{
+	token.ASSIGN:      {},
+	token.PLUS:        {},
+	token.MINUS:       {},
+	token.EXCLAMATION: {},
+	token.CARET:       {},
+	token.ASTERISK:    {},
+	token.SLASH:       {},
+	token.EQUAL:       {},
+	token.NOTEQUAL:    {},
+	token.LESSTHAN:    {},
+	token.GREATERTHAN: {},
+	token.COMMA:       {},
+	token.VAR:         {},
+	token.FUNCTION:    {},
+	token.IF:          {},
+	token.ELSE:        {},
+}
+
+// isComplete reports whether src can be submitted, or the REPL should read more lines first.
+// The input is incomplete if a '(', a '{' or a '[' isn't closed yet, if it ends with an operator (see continuingTokens),
+// or if it ends in a string or a block comment.
+// Anything else is complete, including input with syntax errors, which the parser reports.
+func isComplete(src string) bool {
+	l := lexer.New(src)
+
+	// depth is the number of '(', '{' and '[' not closed yet, the brackets aren't matched, that's up to the parser.
+	depth := 0
+	var last *token.Token
+	for tok := l.ReadNextToken(); tok.Type != token.EOF; tok = l.ReadNextToken() {
+		switch tok.Type {
+		case token.LPAREN, token.LBRACE, token.LBRACKET:
+			depth++
+		case token.RPAREN, token.RBRACE, token.RBRACKET:
+			depth--
+		case token.ILLEGAL:
+			if isUnterminated(tok) {
+				return false
+			}
+		}
+		last = tok
+	}
+
+	if depth > 0 {
+		return false
+	}
+	if last != nil {
+		if _, ok := continuingTokens[last.Type]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// isUnterminated reports whether tok is the error of a string or a block comment that isn't closed before the end of the input.
+func isUnterminated(tok *token.Token) bool {
+	return tok.Literal == lexer.UnterminatedString || tok.Literal == lexer.UnterminatedBlockComment
+}
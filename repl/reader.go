@@ -0,0 +1,86 @@
+package repl
+
+import (
+	"Lisa/internal/lineedit"
+	"Lisa/internal/term"
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+)
+
+// lineReader reads the lines of a session.
+type lineReader interface {
+	// ReadLine prints prompt and reads a line.
+	// It returns io.EOF when the input ends, and lineedit.ErrInterrupted when the user discards the input.
+	ReadLine(prompt string) (string, error)
+}
+
+// newLineReader returns a line editor if in and out are terminals, otherwise lines are simply scanned from in.
+func newLineReader(in io.Reader, out io.Writer, historyFile string, complete func(string) []string) lineReader {
+	inFile, inOK := in.(*os.File)
+	outFile, outOK := out.(*os.File)
+	if inOK && outOK && term.IsTerminal(int(inFile.Fd())) && term.IsTerminal(int(outFile.Fd())) {
+		return newTerminalReader(inFile, outFile, historyFile, complete)
+	}
+	return &scannerReader{scanner: bufio.NewScanner(in), out: out}
+}
+
+// scannerReader reads lines without editing, for input that isn't typed in a terminal, e.g. a pipe.
+type scannerReader struct {
+	scanner *bufio.Scanner
+	out     io.Writer
+}
+
+func (r *scannerReader) ReadLine(prompt string) (string, error) {
+	fmt.Fprint(r.out, prompt)
+	if !r.scanner.Scan() {
+		// The input ended, end the line of the prompt.
+		fmt.Fprintln(r.out)
+		if err := r.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return r.scanner.Text(), nil
+}
+
+// terminalReader reads lines with a line editor, putting the terminal in raw mode while a line is edited.
+type terminalReader struct {
+	fd     int
+	editor *lineedit.Editor
+}
+
+// newTerminalReader creates a terminalReader whose history is persisted in historyFile, unless it's empty.
+// If the history can't be loaded, it's not persisted, and a warning is written to out.
+func newTerminalReader(in, out *os.File, historyFile string, complete func(string) []string) *terminalReader {
+	editor := lineedit.New(in, out)
+	editor.Complete = complete
+	if historyFile != "" {
+		history, err := lineedit.LoadHistory(historyFile)
+		if err != nil {
+			fmt.Fprintf(out, "warning: the history isn't saved: %v\n", err)
+		} else {
+			editor.History = history
+		}
+	}
+	return &terminalReader{fd: int(in.Fd()), editor: editor}
+}
+
+func (r *terminalReader) ReadLine(prompt string) (string, error) {
+	state, err := term.MakeRaw(r.fd)
+	if err != nil {
+		return "", err
+	}
+	line, err := r.editor.ReadLine(prompt)
+	if restoreErr := term.Restore(r.fd, state); err == nil {
+		err = restoreErr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// Failing to save the history isn't worth ending the session for.
+	_ = r.editor.History.Add(line)
+	return line, nil
+}
@@ -1,41 +1,220 @@
+// Package repl implements the interactive read-eval-print loop of Lisa.
 package repl
 
 import (
+	"Lisa/evaluator"
+	"Lisa/internal/lineedit"
 	token "Lisa/lexToken"
 	"Lisa/lexer"
-	"bufio"
+	"Lisa/parser"
+	"errors"
 	"fmt"
 	"io"
-	"sync"
+	"sort"
+	"strings"
 )
 
-const PROMPT = "LISA >>> "
+const (
+	// PROMPT is the default prompt printed before reading a line.
+	PROMPT = "LISA >>> "
+	// CONTINUATION_PROMPT is the default prompt printed before reading a line that continues incomplete input.
+	CONTINUATION_PROMPT = "...   "
+	// QUIT is the default command that ends the session.
+	QUIT = "QUIT"
+)
+
+// Mode decides what the REPL prints for each input.
+type Mode int
+
+const (
+	// ModeEval evaluates the input and prints its value, the zero Mode.
+	ModeEval Mode = iota
+	// ModeAST prints the syntax tree of the input, in its canonical form.
+	ModeAST
+	// ModeTokens prints the tokens of the input, one per line.
+	ModeTokens
+)
 
-var lexerPool = sync.Pool{
-	New: func() any { return new(lexer.Lexer) },
+// modeNames are the names of the modes on the command line.
+var modeNames = map[string]Mode{
+	"eval":   ModeEval,
+	"ast":    ModeAST,
+	"tokens": ModeTokens,
 }
 
-func Start(in io.Reader, out io.Reader) {
-	scanner := bufio.NewScanner(in)
+// ParseMode returns the Mode named name, which is one of 'eval', 'ast' and 'tokens'.
+func ParseMode(name string) (Mode, error) {
+	mode, ok := modeNames[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown mode %q: expected eval, ast or tokens", name)
+	}
+	return mode, nil
+}
 
+// Config configures a REPL session.
+// The empty fields are set to the defaults, so the zero Config is a session with the default prompts and QUIT that evaluates the input.
+type Config struct {
+	// Prompt is printed before reading a line.
+	Prompt string
+	// ContinuationPrompt is printed before reading a line that continues the input, which is incomplete so far.
+	ContinuationPrompt string
+	// HistoryFile is the file the history of the lines is persisted in, if the session runs in a terminal.
+	// The history isn't persisted if it's empty.
+	HistoryFile string
+	// QuitCommand is the line that ends the session, the session also ends when the input does.
+	QuitCommand string
+	Mode        Mode
+}
+
+// withDefaults returns the config with the empty fields set to the defaults.
+func (c Config) withDefaults() Config {
+	if c.Prompt == "" {
+		c.Prompt = PROMPT
+	}
+	if c.ContinuationPrompt == "" {
+		c.ContinuationPrompt = CONTINUATION_PROMPT
+	}
+	if c.QuitCommand == "" {
+		c.QuitCommand = QUIT
+	}
+	return c
+}
+
+// session is the state of a running REPL.
+type session struct {
+	out io.Writer
+	cfg Config
+	// env is where the values are bound, it lives as long as the session, so a definition can be used by the following input.
+	env *evaluator.Environment
+}
+
+// Start runs a REPL session, reading the lines from in and writing the prompts, results and errors to out.
+// The input can span lines, e.g. a function body: as long as it's incomplete (an unclosed bracket, a trailing operator, an unterminated string),
+// the continuation prompt is printed and the next line is appended to it.
+// The values bound by the input are kept for the rest of the session.
+// If in and out are terminals, the lines are read with a line editor, with a history and the completion of names.
+// A line starting with ':' is a meta-command, e.g. ':env' (see metaCommands).
+// It returns when the quit command is read or in is exhausted, the error is the one that stops the reading of in, if any.
+func Start(in io.Reader, out io.Writer, cfg Config) error {
+	s := &session{
+		out: out,
+		cfg: cfg.withDefaults(),
+		env: evaluator.NewEnvironment(),
+	}
+	return s.run(newLineReader(in, out, s.cfg.HistoryFile, s.complete))
+}
+
+// run reads and submits the input until the quit command is read or the input ends.
+func (s *session) run(reader lineReader) error {
+	// input is the lines read so far, which are submitted together once they're complete.
+	var input strings.Builder
 	for {
-		fmt.Printf(PROMPT)
-		scanned := scanner.Scan()
-		if !scanned {
-			return
+		prompt := s.cfg.Prompt
+		if input.Len() != 0 {
+			prompt = s.cfg.ContinuationPrompt
+		}
+		line, err := reader.ReadLine(prompt)
+		if errors.Is(err, lineedit.ErrInterrupted) {
+			// Discard the input, including the lines before.
+			input.Reset()
+			continue
+		}
+		if err != nil {
+			// Whatever is left is submitted, so its errors are reported.
+			if input.Len() != 0 {
+				s.submit(input.String())
+			}
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		if input.Len() == 0 {
+			if line == s.cfg.QuitCommand {
+				return nil
+			}
+			if strings.HasPrefix(strings.TrimSpace(line), ":") {
+				s.runMetaCommand(strings.TrimSpace(line))
+				continue
+			}
 		}
 
-		line := scanner.Text()
-		if line == "QUIT" {
-			break
+		input.WriteString(line)
+		input.WriteString("\n")
+		if !isComplete(input.String()) {
+			continue
 		}
-		l := lexerPool.Get().(*lexer.Lexer)
-		l = lexer.New(line)
-		for tok := l.ReadNextToken(); tok.Type != token.EOF; tok = l.ReadNextToken() {
-			fmt.Printf("%+v\n", tok)
+		s.submit(input.String())
+		input.Reset()
+	}
+}
+
+// complete returns the completions of word: the reserved words and the names bound in the environment starting with it, sorted.
+func (s *session) complete(word string) []string {
+	var completions []string
+	for _, candidate := range append(token.ReservedWords(), s.env.Names()...) {
+		if strings.HasPrefix(candidate, word) {
+			completions = append(completions, candidate)
 		}
+	}
+	sort.Strings(completions)
+	return completions
+}
+
+// submit prints what the mode of the session requires for the input.
+func (s *session) submit(input string) {
+	switch s.cfg.Mode {
+	case ModeTokens:
+		printTokens(s.out, input)
+	case ModeAST:
+		printAST(s.out, input)
+	default:
+		s.eval("", input)
+	}
+}
+
+// printTokens prints the tokens of the input, with their positions.
+func printTokens(out io.Writer, input string) {
+	l := lexer.New(input)
+	for tok := l.ReadNextToken(); tok.Type != token.EOF; tok = l.ReadNextToken() {
+		fmt.Fprintf(out, "%s\t%s\t%q\n", tok.Pos(), tok.Type, tok.Literal)
+	}
+}
+
+// printAST prints the syntax tree of the input, or its syntax errors.
+func printAST(out io.Writer, input string) {
+	p := parser.New(lexer.New(input))
+	root := p.ParseProgram()
+	if printParseErrors(out, p) {
+		return
+	}
+	if len(root.Statements) != 0 {
+		fmt.Fprintln(out, root.String())
+	}
+}
+
+// eval evaluates the input in the environment of the session and prints its value, or its syntax errors.
+// Nothing is printed if the value is null, e.g. for 'var x = 5;'.
+// filename is attached to the positions of the errors, it's empty for the input typed in the session.
+func (s *session) eval(filename, input string) {
+	p := parser.New(lexer.NewFile(filename, input))
+	root := p.ParseProgram()
+	if printParseErrors(s.out, p) {
+		return
+	}
+
+	evaluated := evaluator.Eval(root, s.env)
+	if evaluated != nil && evaluated != evaluator.NULL {
+		fmt.Fprintln(s.out, evaluated.Inspect())
+	}
+}
 
-		l.Free()
-		lexerPool.Put(l)
+// printParseErrors prints the syntax errors the parser found, one per line, and reports whether there's any.
+func printParseErrors(out io.Writer, p *parser.Parser) bool {
+	errs := p.Errors()
+	for _, e := range errs {
+		fmt.Fprintf(out, "\t%s\n", e)
 	}
+	return len(errs) != 0
 }
@@ -0,0 +1,225 @@
+package repl
+
+import (
+	"Lisa/evaluator"
+	"Lisa/internal/lineedit"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestStart(t *testing.T) {
+	testCases := []struct {
+		cfg      Config
+		input    string
+		expected string
+	}{
+		// The zero Config evaluates the lines with the default prompt.
+		{Config{}, "1 + 2;\n", "LISA >>> 3\nLISA >>> \n"},
+		{Config{}, "var x = 5;\n\"a\" + \"b\";\nQUIT\n1;\n", "LISA >>> LISA >>> ab\nLISA >>> "},
+		{Config{}, "1 / 0;", "LISA >>> ERROR: division by zero: 1 / 0\nLISA >>> \n"},
+		{Config{}, "var = 1;\n", "LISA >>> \t1:5: error next token type: expected TYPE(IDENT), got TYPE(=).\nLISA >>> \n"},
+		{Config{Prompt: "> ", QuitCommand: ":q"}, "fn(x) { x; }(7);\n:q\n", "> 7\n> "},
+		{Config{Prompt: "> ", Mode: ModeAST}, "var x = 1 + 2 * 3;\n\n", "> var x = (1 + (2 * 3));\n> > \n"},
+		{Config{Prompt: "> ", Mode: ModeAST}, "var x 1;\n", "> \t1:7: error next token type: expected TYPE(=), got TYPE(INT).\n> \n"},
+		{Config{Prompt: "> ", Mode: ModeTokens}, "x + 1\n", "> 1:1\tIDENT\t\"x\"\n1:3\t+\t\"+\"\n1:5\tINT\t\"1\"\n> \n"},
+		// Incomplete input is continued on the next lines.
+		{Config{}, "var add = fn(x, y) {\n  x + y;\n};\n", "LISA >>> ...   ...   LISA >>> \n"},
+		{Config{}, "fn(x, y) {\n  x + y;\n}(1,\n2);\n", "LISA >>> ...   ...   ...   3\nLISA >>> \n"},
+		{Config{Prompt: "> ", ContinuationPrompt: ". "}, "1 +\n\n2;\n\"multi\nline\";\n", "> . . 3\n> . multi\nline\n> \n"},
+		{Config{Prompt: "> ", ContinuationPrompt: ". ", Mode: ModeAST}, "if (x) {\n/* a\n*/ x; } else\n{ y; }\n", "> . . . if (x) { x; } else { y; };\n> \n"},
+		// The quit command doesn't end the session in the middle of the input.
+		{Config{Prompt: "> ", ContinuationPrompt: ". "}, "(1 +\nQUIT\n);\nQUIT\n", "> . . ERROR: identifier not found: QUIT\n> "},
+		// Incomplete input at the end is submitted as is.
+		{Config{Prompt: "> ", ContinuationPrompt: ". "}, "fn(x) {\n", "> . \n\t2:1: error unterminated block starting at 1:7: expected TYPE(}), got TYPE(EOF).\n"},
+		// A closing bracket too many is complete, the parser reports it.
+		{Config{Prompt: "> "}, "1; }\n", "> \t1:4: error unexpected token \"}\": no prefix parse function for TYPE(}) found.\n> \n"},
+	}
+
+	for i, tc := range testCases {
+		var out bytes.Buffer
+		if err := Start(strings.NewReader(tc.input), &out, tc.cfg); err != nil {
+			t.Errorf("tests[%d] - error running the session: expected nil, got %v.\n", i, err)
+		}
+		if out.String() != tc.expected {
+			t.Errorf("tests[%d] - error output: expected %q, got %q.\n", i, tc.expected, out.String())
+		}
+	}
+}
+
+func TestStart_Environment(t *testing.T) {
+	dir := t.TempDir()
+	lib := filepath.Join(dir, "lib.lisa")
+	if err := os.WriteFile(lib, []byte("var double = fn(x) { x * 2; };\nvar ten = 10;\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	broken := filepath.Join(dir, "broken.lisa")
+	if err := os.WriteFile(broken, []byte("var = 1;\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		// The bindings are kept across the input.
+		{"var x = 5;\nx * 2;\n", "> > 10\n> \n"},
+		{"var add = fn(a, b) {\n a + b;\n};\nadd(1, 2);\nvar x = add(3, 4);\nx;\n", "> . . > 3\n> > 7\n> \n"},
+		// A failing input doesn't remove the bindings.
+		{"var x = 1;\nvar y = x / 0;\nx;\n", "> > ERROR: division by zero: 1 / 0\n> 1\n> \n"},
+		{"var b = true;\nvar a = \"s\";\n:env\n", "> > > a = s\nb = true\n> \n"},
+		{"var x = 5;\n:reset\n:env\nx;\n", "> > > > ERROR: identifier not found: x\n> \n"},
+		{":load " + lib + "\ndouble(ten);\n", "> > 20\n> \n"},
+		{":load " + broken + "\n", "> \t" + broken + ":1:5: error next token type: expected TYPE(IDENT), got TYPE(=).\n> \n"},
+		{":load " + filepath.Join(dir, "missing.lisa") + "\n", "> ERROR: open " + filepath.Join(dir, "missing.lisa") + ": no such file or directory\n> \n"},
+		{"  :env   \n:load\n:reset now\n:nope\n", "> > usage: :load file.lisa\n> usage: :reset\n> unknown command :nope, see :help\n> \n"},
+		{":help\n", "> :env              list the bindings\n:help             list the meta-commands\n:load file.lisa   evaluate a file, its bindings are kept\n:reset            remove all the bindings\nQUIT              end the session\n> \n"},
+	}
+
+	for i, tc := range testCases {
+		var out bytes.Buffer
+		if err := Start(strings.NewReader(tc.input), &out, Config{Prompt: "> ", ContinuationPrompt: ". "}); err != nil {
+			t.Errorf("tests[%d] - error running the session: expected nil, got %v.\n", i, err)
+		}
+		if out.String() != tc.expected {
+			t.Errorf("tests[%d] - error output: expected %q, got %q.\n", i, tc.expected, out.String())
+		}
+	}
+}
+
+// scriptedReader returns the lines, or the errors, one by one.
+type scriptedReader struct {
+	lines []any
+}
+
+func (r *scriptedReader) ReadLine(prompt string) (string, error) {
+	if len(r.lines) == 0 {
+		return "", io.EOF
+	}
+	next := r.lines[0]
+	r.lines = r.lines[1:]
+	if err, ok := next.(error); ok {
+		return "", err
+	}
+	return next.(string), nil
+}
+
+func TestSession_Interrupted(t *testing.T) {
+	var out bytes.Buffer
+	s := &session{out: &out, cfg: Config{}.withDefaults(), env: evaluator.NewEnvironment()}
+
+	// Interrupting discards the input read so far, and the session goes on.
+	err := s.run(&scriptedReader{lines: []any{"var x = fn() {", "1;", lineedit.ErrInterrupted, "var y = 2;", lineedit.ErrInterrupted, "y + 1;"}})
+	if err != nil {
+		t.Errorf("error running the session: expected nil, got %v.\n", err)
+	}
+	if out.String() != "3\n" {
+		t.Errorf("error output: expected %q, got %q.\n", "3\n", out.String())
+	}
+	if _, ok := s.env.Get("x"); ok {
+		t.Errorf("error binding of the discarded input: expected x not to be bound, got bound.\n")
+	}
+}
+
+func TestSession_complete(t *testing.T) {
+	var out bytes.Buffer
+	s := &session{out: &out, cfg: Config{}.withDefaults(), env: evaluator.NewEnvironment()}
+	s.run(&scriptedReader{lines: []any{"var fib = 1;", "var result = 2;", "var foo = 3;"}})
+
+	testCases := []struct {
+		word     string
+		expected string
+	}{
+		{"f", "[false fib fn foo]"},
+		{"fo", "[foo]"},
+		{"re", "[result return]"},
+		{"v", "[var]"},
+		{"z", "[]"},
+		{"", "[else false fib fn foo if result return true var]"},
+	}
+
+	for i, tc := range testCases {
+		if got := fmt.Sprint(s.complete(tc.word)); got != tc.expected {
+			t.Errorf("tests[%d] - error completions of %q: expected %s, got %s.\n", i, tc.word, tc.expected, got)
+		}
+	}
+}
+
+func Test_isComplete(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected bool
+	}{
+		{"", true},
+		{"1 + 2;", true},
+		{"var f = fn(x) {", false},
+		{"var f = fn(x) { x; }", true},
+		{"add(1,", false},
+		{"add(1, 2)", true},
+		{"((1 + 2)", false},
+		{"var arr = [1,", false},
+		{"var arr = [1, 2];", true},
+		{"arr[1:", false},
+		{`var h = {"a":`, false},
+		{`var h = {"a": 1};`, true},
+		{"1 +", false},
+		{"1 + // comment", false},
+		{"var x =", false},
+		{"x ==", false},
+		{"if (x) { 1; } else", false},
+		{"var", false},
+		{"return", true},
+		{`"unterminated`, false},
+		{`"terminated"`, true},
+		{`"bad \q escape"`, true},
+		{"/* unterminated", false},
+		{"/* terminated */", true},
+		{"1; }", true},
+		{"#", true},
+	}
+
+	for i, tc := range testCases {
+		if got := isComplete(tc.input); got != tc.expected {
+			t.Errorf("tests[%d] - error completeness of %q: expected %t, got %t.\n", i, tc.input, tc.expected, got)
+		}
+	}
+}
+
+func TestStart_ReadError(t *testing.T) {
+	readErr := errors.New("connection lost")
+
+	var out bytes.Buffer
+	err := Start(iotest.ErrReader(readErr), &out, Config{})
+	if !errors.Is(err, readErr) {
+		t.Errorf("error running the session: expected %v, got %v.\n", readErr, err)
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	testCases := []struct {
+		name         string
+		expectedMode Mode
+		expectedErr  bool
+	}{
+		{"eval", ModeEval, false},
+		{"ast", ModeAST, false},
+		{"tokens", ModeTokens, false},
+		{"run", 0, true},
+	}
+
+	for i, tc := range testCases {
+		mode, err := ParseMode(tc.name)
+		if (err != nil) != tc.expectedErr {
+			t.Errorf("tests[%d] - error parsing mode %q: expected error %t, got %v.\n", i, tc.name, tc.expectedErr, err)
+		}
+		if mode != tc.expectedMode {
+			t.Errorf("tests[%d] - error mode: expected %d, got %d.\n", i, tc.expectedMode, mode)
+		}
+	}
+}